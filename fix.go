@@ -0,0 +1,237 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"spell-checker-cli/spellchecker"
+)
+
+// fixSession is an interactive review of the typos of a check, for the fix command.
+// Choices are read from in and the typos shown on out; nothing is written to disk
+// until save is called.
+type fixSession struct {
+	in  *bufio.Reader
+	out io.Writer
+	// color highlights typos with ANSI escapes rather than with ">>" and "<<".
+	color bool
+	// personal is the personal dictionary file that words are added to, if any.
+	personal string
+
+	edits map[string][]spellchecker.FixEdit
+	// ignored holds the normalized words that are not asked about again.
+	ignored map[string]bool
+	added   []string
+	quit    bool
+}
+
+func newFixSession(in io.Reader, out io.Writer, personal string) *fixSession {
+	return &fixSession{
+		in:       bufio.NewReader(in),
+		out:      out,
+		personal: personal,
+		edits:    make(map[string][]spellchecker.FixEdit),
+		ignored:  make(map[string]bool),
+	}
+}
+
+// runFix reviews results interactively on the terminal, then applies the chosen edits
+// and dictionary additions.
+func runFix(results map[string][]spellchecker.MisspelledWord, personal string) error {
+	if len(results) == 0 {
+		fmt.Println("No typos found.")
+		return nil
+	}
+	s := newFixSession(os.Stdin, os.Stdout, personal)
+	s.color = isTerminal(os.Stdout) && os.Getenv("NO_COLOR") == ""
+	if err := s.review(results); err != nil {
+		return err
+	}
+	if err := s.save(); err != nil {
+		return err
+	}
+
+	fmt.Printf("\nFixed %d typos in %d files.\n", countEdits(s.edits), len(s.edits))
+	if len(s.added) > 0 {
+		fmt.Printf("Added %d words to %s.\n", len(s.added), s.personal)
+	}
+	return nil
+}
+
+// review asks what to do with each typo of results, file by file, until every typo
+// has been reviewed or the user quits.
+func (s *fixSession) review(results map[string][]spellchecker.MisspelledWord) error {
+	for _, path := range slices.Sorted(maps.Keys(results)) {
+		// Without its lines, a typo is still shown, only without context.
+		lines := readLines(path)
+		for _, typo := range results[path] {
+			if typo.Rule == spellchecker.RuleUnusedDirective || s.ignored[ignoreKey(typo.Word)] {
+				continue
+			}
+			if err := s.ask(path, lines, typo); err != nil {
+				return err
+			}
+			if s.quit {
+				return nil
+			}
+		}
+	}
+	return nil
+}
+
+// ask shows one typo in its line and records the user's choice.
+func (s *fixSession) ask(path string, lines []string, typo spellchecker.MisspelledWord) error {
+	described := typo
+	described.Suggestions = nil
+	fmt.Fprintf(s.out, "\n%s:%d:%d: %s\n", path, typo.LineNumber, typo.Column, described.Message())
+	if typo.LineNumber >= 1 && typo.LineNumber <= len(lines) {
+		fmt.Fprintf(s.out, "  %s\n", s.highlight(lines[typo.LineNumber-1], typo.Column, typo.Word))
+	}
+	for i, suggestion := range typo.Suggestions {
+		fmt.Fprintf(s.out, "  %d) %s\n", i+1, suggestion)
+	}
+	fmt.Fprintln(s.out, "  r) replace with...  i) ignore once  I) ignore all  a) add to dictionary  q) save and quit")
+
+	for {
+		answer, err := s.prompt("> ")
+		if err == io.EOF {
+			s.quit = true
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		switch answer {
+		case "":
+			continue
+		case "i":
+			return nil
+		case "I":
+			s.ignored[ignoreKey(typo.Word)] = true
+			return nil
+		case "a":
+			if s.personal == "" {
+				fmt.Fprintln(s.out, "No personal dictionary is configured; set --personal-dict to add words.")
+				continue
+			}
+			s.added = append(s.added, typo.Word)
+			s.ignored[ignoreKey(typo.Word)] = true
+			return nil
+		case "q":
+			s.quit = true
+			return nil
+		case "r":
+			replacement, err := s.prompt("Replace with: ")
+			if err == io.EOF {
+				s.quit = true
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			if replacement == "" {
+				continue
+			}
+			s.edit(path, typo, replacement)
+			return nil
+		}
+
+		if n, err := strconv.Atoi(answer); err == nil && n >= 1 && n <= len(typo.Suggestions) {
+			s.edit(path, typo, spellchecker.MatchCase(typo.Word, typo.Suggestions[n-1]))
+			return nil
+		}
+		fmt.Fprintf(s.out, "Unknown choice %q.\n", answer)
+	}
+}
+
+// prompt prints prompt and returns the next line of input, trimmed.
+func (s *fixSession) prompt(prompt string) (string, error) {
+	fmt.Fprint(s.out, prompt)
+	line, err := s.in.ReadString('\n')
+	if err == io.EOF && line != "" {
+		err = nil
+	}
+	return strings.TrimSpace(line), err
+}
+
+func (s *fixSession) edit(path string, typo spellchecker.MisspelledWord, replacement string) {
+	s.edits[path] = append(s.edits[path], spellchecker.FixEdit{Line: typo.LineNumber, Column: typo.Column, Old: typo.Word, New: replacement})
+}
+
+// highlight marks the word at the 1-based rune column of line.
+func (s *fixSession) highlight(line string, column int, word string) string {
+	runes := []rune(line)
+	start, end := column-1, column-1+len([]rune(word))
+	if start < 0 || end > len(runes) {
+		return line
+	}
+	before, after := ">>", "<<"
+	if s.color {
+		before, after = "\x1b[1;31m", "\x1b[0m"
+	}
+	return string(runes[:start]) + before + string(runes[start:end]) + after + string(runes[end:])
+}
+
+// save applies the edits to their files and appends the added words to the personal
+// dictionary.
+func (s *fixSession) save() error {
+	if err := spellchecker.ApplyAutoFixes(s.edits, nil); err != nil {
+		return err
+	}
+	if len(s.added) > 0 {
+		if err := spellchecker.AppendPersonalWords(s.personal, s.added); err != nil {
+			return fmt.Errorf("adding words to %s: %w", s.personal, err)
+		}
+	}
+	return nil
+}
+
+// ignoreKey returns the key under which "ignore all" and "add to dictionary" record
+// word, so that other casings of the word are skipped too.
+func ignoreKey(word string) string {
+	return strings.ToLower(strings.ReplaceAll(word, "’", "'"))
+}
+
+// readLines returns the lines of the file path, or nil if it cannot be read.
+func readLines(path string) []string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	lines := strings.Split(string(data), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSuffix(line, "\r")
+	}
+	return lines
+}
+
+// countEdits returns the total number of edits.
+func countEdits(edits map[string][]spellchecker.FixEdit) int {
+	n := 0
+	for _, fileEdits := range edits {
+		n += len(fileEdits)
+	}
+	return n
+}
+
+// countTypos returns the total number of typos in results.
+func countTypos(results map[string][]spellchecker.MisspelledWord) int {
+	n := 0
+	for _, typos := range results {
+		n += len(typos)
+	}
+	return n
+}
+
+// isTerminal reports whether f is an interactive terminal.
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
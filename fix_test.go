@@ -0,0 +1,93 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"spell-checker-cli/spellchecker"
+)
+
+func TestFixSessionReview(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "notes.txt")
+	content := "teh cat\r\nwrld and wrld\r\nqwzx teh\r\nlast"
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	personal := filepath.Join(dir, "words.txt")
+	os.WriteFile(personal, []byte("# project words\nkubelet"), 0644)
+
+	results := map[string][]spellchecker.MisspelledWord{path: {
+		{Word: "teh", LineNumber: 1, Column: 1, Suggestions: []string{"the", "ten"}},
+		{Word: "wrld", LineNumber: 2, Column: 1, Suggestions: []string{"world"}},
+		{Word: "wrld", LineNumber: 2, Column: 10, Suggestions: []string{"world"}},
+		{Word: "qwzx", LineNumber: 3, Column: 1},
+		{Word: "teh", LineNumber: 3, Column: 6, Suggestions: []string{"the"}},
+		{Word: "last", LineNumber: 4, Column: 1},
+	}}
+	// Pick a suggestion, retry after an unknown choice, ignore once, add to the
+	// dictionary, replace by hand, then quit at the last typo.
+	input := strings.Join([]string{"1", "9", "1", "i", "a", "r", "", "r", "Teh", "q"}, "\n") + "\n"
+	var out bytes.Buffer
+	s := newFixSession(strings.NewReader(input), &out, personal)
+	if err := s.review(results); err != nil {
+		t.Fatalf("review() error = %v", err)
+	}
+	if err := s.save(); err != nil {
+		t.Fatalf("save() error = %v", err)
+	}
+
+	got, _ := os.ReadFile(path)
+	if want := "the cat\r\nworld and wrld\r\nqwzx Teh\r\nlast"; string(got) != want {
+		t.Errorf("fixed file = %q, want %q", got, want)
+	}
+	if info, _ := os.Stat(path); info.Mode().Perm() != 0600 {
+		t.Errorf("fixed file mode = %v, want 0600", info.Mode().Perm())
+	}
+	words, _ := os.ReadFile(personal)
+	if want := "# project words\nkubelet\nqwzx\n"; string(words) != want {
+		t.Errorf("personal dictionary = %q, want %q", words, want)
+	}
+	for _, want := range []string{"notes.txt:1:1: \"teh\" appears to be a typo.", "  >>teh<< cat", "  1) the", "Unknown choice \"9\"."} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output does not contain %q:\n%s", want, out.String())
+		}
+	}
+	if !s.quit {
+		t.Error("review() did not quit")
+	}
+}
+
+func TestFixSessionIgnoreAll(t *testing.T) {
+	results := map[string][]spellchecker.MisspelledWord{"a.txt": {
+		{Word: "Wrld", LineNumber: 1, Column: 1},
+		{Word: "wrld", LineNumber: 2, Column: 1},
+		{Word: "teh", LineNumber: 3, Column: 1},
+	}}
+	var out bytes.Buffer
+	// Without a personal dictionary, "a" is refused; the input then ends.
+	s := newFixSession(strings.NewReader("I\na\n"), &out, "")
+	if err := s.review(results); err != nil {
+		t.Fatalf("review() error = %v", err)
+	}
+	if strings.Count(out.String(), "appears to be a typo") != 2 {
+		t.Errorf("want the second wrld to be skipped:\n%s", out.String())
+	}
+	if !strings.Contains(out.String(), "No personal dictionary is configured") {
+		t.Errorf("want adding a word to be refused:\n%s", out.String())
+	}
+	if !s.quit || len(s.added) != 0 || len(s.edits) != 0 {
+		t.Errorf("session = quit %v, added %v, edits %v; want quit with no changes", s.quit, s.added, s.edits)
+	}
+}
+
+func TestCountEditsAndTypos(t *testing.T) {
+	edits := map[string][]spellchecker.FixEdit{"a.txt": {{}, {}}, "b.txt": {{}}}
+	results := map[string][]spellchecker.MisspelledWord{"a.txt": {{}}, "c.txt": {{}, {}, {}}}
+	if countEdits(edits) != 3 || countTypos(results) != 4 {
+		t.Errorf("countEdits() = %d, countTypos() = %d; want 3 and 4", countEdits(edits), countTypos(results))
+	}
+}
@@ -1,28 +1,78 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"slices"
 	"strings"
+	"syscall"
+	"time"
 
 	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
+
+	"spell-checker-cli/spellchecker"
 )
 
 type Config struct {
-	// Exclude is a list of file patterns to exclude.
+	// Exclude is a list of glob patterns for files and directories to skip.
 	Exclude []string `mapstructure:"exclude"`
+	// Include, when not empty, is a list of glob patterns for the only files to check.
+	// Exclude takes precedence over it.
+	Include []string `mapstructure:"include"`
 	// Dictionary is the path to a custom dictionary file.
 	Dictionary string `mapstructure:"dictionary"`
 	// PersonalDictionary is the path to a personal word list.
 	PersonalDictionary string `mapstructure:"personal-dictionary"`
-	// Format is the output format (txt, html).
+	// Format is the output format (txt, html, json, sarif, junit, checkstyle, github, gitlab).
 	Format string `mapstructure:"format"`
 	// Verbose enables verbose logging.
 	Verbose bool `mapstructure:"verbose"`
 	// Output is the path for the report file or directory.
 	Output string `mapstructure:"output"`
+	// CheckStrings also checks string literals in source files, not only comments.
+	CheckStrings bool `mapstructure:"check-strings"`
+	// SplitIdentifiers checks camelCase and PascalCase words part by part.
+	SplitIdentifiers bool `mapstructure:"split-identifiers"`
+	// MaxSuggestions is the number of suggestions shown per typo (0 for all).
+	MaxSuggestions int `mapstructure:"max-suggestions"`
+	// Distance is the edit distance metric for suggestions (damerau, levenshtein).
+	Distance string `mapstructure:"distance"`
+	// Phonetic also suggests words that sound like the typo.
+	Phonetic bool `mapstructure:"phonetic"`
+	// ReportUnusedDirectives reports inline spellchecker: directives that suppress nothing.
+	ReportUnusedDirectives bool `mapstructure:"report-unused-directives"`
+	// NoIgnore disables .gitignore, .git/info/exclude and .spellcheckignore files.
+	NoIgnore bool `mapstructure:"no-ignore"`
+	// DiffBase restricts the check to the lines changed since the branch diverged from
+	// this Git revision.
+	DiffBase string `mapstructure:"diff-base"`
+	// DiffFile restricts the check to the lines added by a unified diff file ("-" for stdin).
+	DiffFile string `mapstructure:"diff-file"`
+	// Baseline is the path to a baseline file; findings recorded in it are not reported.
+	Baseline string `mapstructure:"baseline"`
+	// WriteBaseline is the path to which the current findings are written as a baseline.
+	WriteBaseline string `mapstructure:"write-baseline"`
+	// Fix replaces typos that have a confident suggestion or a replacement in Replace.
+	Fix bool `mapstructure:"fix"`
+	// DryRun prints the changes Fix would make as a unified diff instead of making them.
+	DryRun bool `mapstructure:"dry-run"`
+	// Replace lists "typo=replacement" pairs for Fix.
+	Replace []string `mapstructure:"replace"`
+	// Watch keeps running after the check, checking files again as they change.
+	Watch bool `mapstructure:"watch"`
+	// StdinFilename is the name standard input is checked and reported as, when the
+	// path "-" is given. Its extension selects how the text is checked.
+	StdinFilename string `mapstructure:"stdin-filename"`
+	// FailOnError exits with an error status when a file or directory could not be
+	// checked, such as an unreadable one.
+	FailOnError bool `mapstructure:"fail-on-error"`
+	// Timeout, when not zero, stops the check and fails once it has taken this long.
+	Timeout time.Duration `mapstructure:"timeout"`
 }
 
 // loadConfig initializes flags and loads configuration from a file and flags.
@@ -30,12 +80,31 @@ type Config struct {
 func loadConfig() (*Config, error) {
 	// --- Define Flags using pflag ---
 	// pflag is a drop-in replacement for Go's flag package with more features.
-	pflag.StringSlice("exclude", []string{}, "Optional: comma-separated list of file patterns to exclude.")
-	pflag.String("dict", "", "Optional: path to a custom CSV dictionary file.")
+	pflag.StringSlice("exclude", []string{}, "Optional: comma-separated list of glob patterns for files and directories to exclude.")
+	pflag.StringSlice("include", []string{}, "Optional: comma-separated list of glob patterns; only matching files are checked.")
+	pflag.String("dict", "", "Optional: path to a custom CSV dictionary file, or a Hunspell .dic/.aff file.")
 	pflag.String("personal-dict", "", "Optional: path to a personal dictionary file (one word per line).")
 	pflag.String("output", "", "Optional: path to an output file or directory (for HTML reports).")
-	pflag.String("format", "", "Optional: output format (txt, html). Overrides filename extension.")
+	pflag.String("format", "", "Optional: output format (txt, html, json, sarif, junit, checkstyle, github, gitlab). Overrides filename extension.")
 	pflag.Bool("verbose", false, "Enable verbose logging to show skipped files and directories.")
+	pflag.Bool("check-strings", false, "Also check string literals in source files, not only comments.")
+	pflag.Bool("split-identifiers", false, "Check the words of camelCase and PascalCase identifiers separately.")
+	pflag.Int("max-suggestions", 5, "Maximum number of suggestions shown per typo (0 for all).")
+	pflag.String("distance", spellchecker.MetricDamerau, "Edit distance for suggestions (damerau, levenshtein).")
+	pflag.Bool("phonetic", false, "Also suggest words that sound like the typo (Double Metaphone).")
+	pflag.Bool("report-unused-directives", false, "Report inline spellchecker: directives that suppress no typo.")
+	pflag.Bool("no-ignore", false, "Do not read .gitignore, .git/info/exclude or .spellcheckignore files.")
+	pflag.String("diff-base", "", "Optional: only check lines changed since the branch diverged from this Git revision.")
+	pflag.String("baseline", "", "Optional: path to a baseline file; only findings missing from it are reported.")
+	pflag.String("write-baseline", "", "Optional: write the current findings to this baseline file and exit successfully.")
+	pflag.Bool("fix", false, "Fix typos that have a single confident suggestion or a --replace entry, keeping their case.")
+	pflag.Bool("dry-run", false, "With --fix, print the fixes as a unified diff instead of applying them.")
+	pflag.StringSlice("replace", []string{}, "Optional: comma-separated typo=replacement pairs for --fix.")
+	pflag.String("diff-file", "", "Optional: only check lines added by this unified diff (\"-\" for standard input).")
+	pflag.Bool("watch", false, "Keep running and check files again whenever they change.")
+	pflag.String("stdin-filename", "stdin", "Name standard input (\"-\") is checked and reported as; its extension selects the file type.")
+	pflag.Bool("fail-on-error", false, "Exit with status 1 when a file or directory could not be checked, such as an unreadable one.")
+	pflag.Duration("timeout", 0, "Optional: stop and fail when the check takes longer than this, e.g. 30s or 2m.")
 	pflag.Parse()
 
 	// --- Initialize Viper ---
@@ -50,11 +119,30 @@ func loadConfig() (*Config, error) {
 	// --- Bind pflags to Viper ---
 	// This tells Viper to check the flag value if a key is not found in the config file.
 	v.BindPFlag("exclude", pflag.Lookup("exclude"))
+	v.BindPFlag("include", pflag.Lookup("include"))
 	v.BindPFlag("dictionary", pflag.Lookup("dict"))
 	v.BindPFlag("personal-dictionary", pflag.Lookup("personal-dict"))
 	v.BindPFlag("output", pflag.Lookup("output"))
 	v.BindPFlag("format", pflag.Lookup("format"))
 	v.BindPFlag("verbose", pflag.Lookup("verbose"))
+	v.BindPFlag("check-strings", pflag.Lookup("check-strings"))
+	v.BindPFlag("split-identifiers", pflag.Lookup("split-identifiers"))
+	v.BindPFlag("max-suggestions", pflag.Lookup("max-suggestions"))
+	v.BindPFlag("distance", pflag.Lookup("distance"))
+	v.BindPFlag("phonetic", pflag.Lookup("phonetic"))
+	v.BindPFlag("report-unused-directives", pflag.Lookup("report-unused-directives"))
+	v.BindPFlag("no-ignore", pflag.Lookup("no-ignore"))
+	v.BindPFlag("diff-base", pflag.Lookup("diff-base"))
+	v.BindPFlag("diff-file", pflag.Lookup("diff-file"))
+	v.BindPFlag("baseline", pflag.Lookup("baseline"))
+	v.BindPFlag("write-baseline", pflag.Lookup("write-baseline"))
+	v.BindPFlag("fix", pflag.Lookup("fix"))
+	v.BindPFlag("dry-run", pflag.Lookup("dry-run"))
+	v.BindPFlag("replace", pflag.Lookup("replace"))
+	v.BindPFlag("watch", pflag.Lookup("watch"))
+	v.BindPFlag("stdin-filename", pflag.Lookup("stdin-filename"))
+	v.BindPFlag("fail-on-error", pflag.Lookup("fail-on-error"))
+	v.BindPFlag("timeout", pflag.Lookup("timeout"))
 
 	// --- Read Config File ---
 	// Find and read the config file.
@@ -70,6 +158,28 @@ func loadConfig() (*Config, error) {
 	if err := v.Unmarshal(&cfg); err != nil {
 		return nil, fmt.Errorf("error unmarshaling config: %w", err)
 	}
+	if err := checkOptions(&cfg).Validate(); err != nil {
+		return nil, err
+	}
+	if cfg.DiffBase != "" && cfg.DiffFile != "" {
+		return nil, fmt.Errorf("diff-base and diff-file cannot be used together")
+	}
+	if cfg.DryRun && !cfg.Fix {
+		return nil, fmt.Errorf("dry-run can only be used with fix")
+	}
+	if cfg.Watch && (cfg.Fix || cfg.WriteBaseline != "" || cfg.DiffBase != "" || cfg.DiffFile != "") {
+		return nil, fmt.Errorf("watch cannot be used with fix, dry-run, write-baseline, diff-base or diff-file")
+	}
+	if pflag.Arg(0) == "fix" && (cfg.Fix || cfg.Watch) {
+		// Both would rewrite the same files, or the review would go stale.
+		return nil, fmt.Errorf("the fix command cannot be used with fix, dry-run or watch")
+	}
+	if _, err := spellchecker.ParseReplacements(cfg.Replace); err != nil {
+		return nil, err
+	}
+	if _, err := spellchecker.ReportFormat(cfg.Format, cfg.Output); err != nil {
+		return nil, err
+	}
 
 	return &cfg, nil
 }
@@ -82,75 +192,343 @@ func main() {
 		os.Exit(1)
 	}
 
-	dictionary, err := loadDictionary(cfg.Dictionary)
+	switch ext := strings.ToLower(filepath.Ext(cfg.Dictionary)); {
+	case cfg.Dictionary == "":
+		fmt.Fprintln(os.Stderr, "Loading dictionary from embedded data.")
+	case ext == ".dic" || ext == ".aff":
+		base := strings.TrimSuffix(cfg.Dictionary, filepath.Ext(cfg.Dictionary))
+		fmt.Fprintf(os.Stderr, "Loading Hunspell dictionary from: %s.dic / %s.aff\n", base, base)
+	default:
+		fmt.Fprintf(os.Stderr, "Loading custom dictionary from: %s\n", cfg.Dictionary)
+	}
+	dictionary, err := spellchecker.LoadDictionary(cfg.Dictionary)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Fatal error loading dictionary: %v\n", err)
 		os.Exit(1)
 	}
-	fmt.Printf("Successfully loaded %d words.\n", len(dictionary))
+	fmt.Fprintf(os.Stderr, "Successfully loaded %d words.\n", len(dictionary.Words))
 
 	if cfg.PersonalDictionary != "" {
-		count, err := loadPersonalDictionary(cfg.PersonalDictionary, dictionary)
+		count, err := spellchecker.LoadPersonalDictionary(cfg.PersonalDictionary, dictionary)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error loading personal dictionary: %v\n", err)
 			os.Exit(1)
 		}
-		fmt.Printf("Successfully loaded and merged %d words from personal dictionary.\n", count)
+		fmt.Fprintf(os.Stderr, "Successfully loaded and merged %d words from personal dictionary.\n", count)
+	}
+	dictionary.BuildIndex()
+	if cfg.Phonetic {
+		dictionary.BuildPhoneticIndex()
 	}
 
-	if pflag.NArg() < 1 {
-		fmt.Println("Usage: spellchecker [flags] <file_or_directory>")
+	opts := checkOptions(cfg)
+
+	// "fix" reviews the typos interactively instead of reporting them, and "lsp"
+	// serves editors over standard input and output.
+	args := pflag.Args()
+	command := ""
+	if len(args) > 0 && (args[0] == "fix" || args[0] == "lsp") {
+		command, args = args[0], args[1:]
+	}
+	if command == "lsp" {
+		if err := spellchecker.NewChecker(dictionary, opts).ServeLSP(os.Stdin, os.Stdout, cfg.PersonalDictionary); err != nil {
+			fmt.Fprintf(os.Stderr, "Language server error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+	if len(args) < 1 {
+		fmt.Fprintln(os.Stderr, "Usage: spellchecker [flags] <file_or_directory>... (\"-\" for standard input)")
+		fmt.Fprintln(os.Stderr, "       spellchecker [flags] fix <file_or_directory>...")
+		fmt.Fprintln(os.Stderr, "       spellchecker [flags] lsp")
 		os.Exit(1)
 	}
 
-	path := pflag.Arg(0)
-	allTypos, err := runConcurrentChecker(path, dictionary, cfg.Exclude, cfg.Verbose)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error processing path: %v\n", err)
+	paths := uniquePaths(args)
+	if cfg.Watch && len(paths) > 1 {
+		fmt.Fprintln(os.Stderr, "Fatal error: --watch takes a single path")
+		os.Exit(1)
+	}
+	if slices.Contains(paths, "-") && (command == "fix" || cfg.Fix || cfg.Watch || cfg.Baseline != "" || cfg.WriteBaseline != "" || cfg.DiffBase != "" || cfg.DiffFile != "") {
+		// These work on files: they rewrite them, watch them or read their lines again.
+		fmt.Fprintln(os.Stderr, "Fatal error: standard input cannot be checked with the fix command, --fix, --dry-run, --watch, --baseline, --write-baseline, --diff-base or --diff-file")
 		os.Exit(1)
 	}
 
-	// --- REVISED OUTPUT LOGIC ---
-	if cfg.Output == "" {
-		// Default case: No output path provided, so print a text report to standard output.
-		generateTextReport(os.Stdout, allTypos)
-	} else {
-		// An output path was provided. Determine the format and mode.
-		format := strings.ToLower(cfg.Format)
-		ext := strings.ToLower(filepath.Ext(cfg.Output))
-
-		// Determine if the desired format is HTML.
-		isHTML := format == "html" || (format == "" && ext == ".html")
+	if cfg.DiffFile != "" {
+		// Paths in the diff are relative to the current directory, whatever the path checked.
+		if opts.Changes, err = spellchecker.ReadDiffFile(cfg.DiffFile); err != nil {
+			fmt.Fprintf(os.Stderr, "Fatal error reading changes: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Fprintf(os.Stderr, "Checking the changed lines of %d files.\n", opts.Changes.Len())
+	}
 
-		// NEW: Determine if we should use the multi-file directory mode for HTML.
-		// This is triggered if the format is HTML AND the path does not end in ".html".
-		isMultiFileDir := isHTML && ext != ".html"
+	// The first interrupt stops the check, or the watch, cleanly; a second one exits
+	// at once.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+	checkCtx := ctx
+	if cfg.Timeout > 0 {
+		var cancel context.CancelFunc
+		checkCtx, cancel = context.WithTimeout(ctx, cfg.Timeout)
+		defer cancel()
+	}
 
-		if isMultiFileDir {
-			fmt.Printf("Generating multi-file HTML report in directory: %s\n", cfg.Output)
-			if err := generateMultiFileHTMLReport(cfg.Output, allTypos); err != nil {
-				fmt.Fprintf(os.Stderr, "Error generating multi-file report: %v\n", err)
-				os.Exit(1)
+	allTypos := make(map[string][]spellchecker.MisspelledWord)
+	summary := spellchecker.ScanSummary{DictionarySize: len(dictionary.Words)}
+	for _, path := range paths {
+		if path == "-" {
+			result, err := spellchecker.NewChecker(dictionary, opts).CheckReader(checkCtx, cfg.StdinFilename, os.Stdin)
+			if checkCtx.Err() != nil {
+				stopped(cfg, checkCtx.Err())
 			}
-		} else {
-			// Fallback to single-file output for text reports or specific HTML files.
-			file, err := os.Create(cfg.Output)
 			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error creating output file: %v\n", err)
+				fmt.Fprintf(os.Stderr, "Error reading standard input: %v\n", err)
 				os.Exit(1)
 			}
-			defer file.Close()
+			if len(result.Typos) > 0 {
+				allTypos[result.FilePath] = result.Typos
+			}
+			summary.Merge(spellchecker.ScanSummary{Files: []string{cfg.StdinFilename}})
+			continue
+		}
+
+		pathOpts, err := pathOptions(cfg, path, opts)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Fatal error reading changes: %v\n", err)
+			os.Exit(1)
+		}
+		typos, pathSummary, err := spellchecker.NewChecker(dictionary, pathOpts).CheckPath(checkCtx, path)
+		if err != nil {
+			stopped(cfg, err)
+		}
+		for file, fileTypos := range typos {
+			allTypos[file] = fileTypos
+		}
+		summary.Merge(pathSummary)
+	}
+	if !cfg.Watch {
+		// Interrupts stop the rest, such as the fix command's prompts, as usual.
+		stop()
+	}
+	if len(summary.Errors) > 0 {
+		fmt.Fprintf(os.Stderr, "%d files or directories could not be checked; they are listed in the report.\n", len(summary.Errors))
+	}
+	failed := cfg.FailOnError && len(summary.Errors) > 0
+
+	// --- BASELINE ---
+	if cfg.WriteBaseline != "" {
+		b, err := spellchecker.NewBaseline(cfg.WriteBaseline, allTypos)
+		if err == nil {
+			err = b.Write(cfg.WriteBaseline)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing baseline: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Fprintf(os.Stderr, "Wrote %d findings to baseline %s.\n", b.Size(), cfg.WriteBaseline)
+		return
+	}
+	if cfg.Watch {
+		report := func(results map[string][]spellchecker.MisspelledWord, summary spellchecker.ScanSummary) error {
+			if cfg.Baseline != "" {
+				var err error
+				if results, err = applyBaseline(cfg.Baseline, results, summary.Files); err != nil {
+					return err
+				}
+			}
+			if info, err := os.Stdout.Stat(); cfg.Output == "" && err == nil && info.Mode()&os.ModeCharDevice != 0 {
+				// Each report replaces the previous one on the screen.
+				fmt.Print("\x1b[H\x1b[2J")
+			}
+			return writeOutput(cfg, results, summary)
+		}
+		if err := report(allTypos, summary); err != nil {
+			fmt.Fprintf(os.Stderr, "Error %v\n", err)
+			os.Exit(1)
+		}
+		// --watch takes a single path, and no diff: its options were built without error.
+		watchOpts, _ := pathOptions(cfg, paths[0], opts)
+		if err := spellchecker.NewChecker(dictionary, watchOpts).Watch(ctx, paths[0], allTypos, summary, report); err != nil {
+			fmt.Fprintf(os.Stderr, "Error watching %s: %v\n", paths[0], err)
+			os.Exit(1)
+		}
+		return
+	}
+	if cfg.Baseline != "" {
+		allTypos, err = applyBaseline(cfg.Baseline, allTypos, summary.Files)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error %v\n", err)
+			os.Exit(1)
+		}
+	}
 
-			fmt.Printf("Report will be saved to: %s\n", cfg.Output)
-			if isHTML {
-				generateHTMLReport(file, allTypos)
-			} else {
-				generateTextReport(file, allTypos)
+	if command == "fix" {
+		if err := runFix(allTypos, cfg.PersonalDictionary); err != nil {
+			fmt.Fprintf(os.Stderr, "Error fixing typos: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if cfg.Fix {
+		// The replacements were validated when the configuration was loaded.
+		replacements, _ := spellchecker.ParseReplacements(cfg.Replace)
+		edits, remaining := spellchecker.PlanAutoFixes(allTypos, dictionary, replacements, opts.Suggestions)
+		if cfg.DryRun {
+			if err := spellchecker.ApplyAutoFixes(edits, os.Stdout); err != nil {
+				fmt.Fprintf(os.Stderr, "Error previewing fixes: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Fprintf(os.Stderr, "Would fix %d typos in %d files; %d typos need review.\n", countEdits(edits), len(edits), countTypos(remaining))
+			if len(allTypos) > 0 || failed {
+				os.Exit(1)
 			}
+			return
+		}
+		if err := spellchecker.ApplyAutoFixes(edits, nil); err != nil {
+			fmt.Fprintf(os.Stderr, "Error fixing typos: %v\n", err)
+			os.Exit(1)
 		}
+		fmt.Fprintf(os.Stderr, "Fixed %d typos in %d files.\n", countEdits(edits), len(edits))
+		allTypos = remaining
 	}
 
-	if len(allTypos) > 0 {
+	// --- OUTPUT LOGIC ---
+	if err := writeOutput(cfg, allTypos, summary); err != nil {
+		fmt.Fprintf(os.Stderr, "Error %v\n", err)
 		os.Exit(1)
 	}
+
+	if len(allTypos) > 0 || failed {
+		os.Exit(1)
+	}
+}
+
+// stopped exits after the check was interrupted, timed out or failed with err.
+func stopped(cfg *Config, err error) {
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		fmt.Fprintf(os.Stderr, "Fatal error: the check did not finish within %s\n", cfg.Timeout)
+		os.Exit(1)
+	case errors.Is(err, context.Canceled):
+		fmt.Fprintln(os.Stderr, "Interrupted.")
+		// The status of a process stopped by SIGINT, as shells report it.
+		os.Exit(130)
+	}
+	fmt.Fprintf(os.Stderr, "Error processing path: %v\n", err)
+	os.Exit(1)
+}
+
+// checkOptions returns the options of the checks from the configuration.
+func checkOptions(cfg *Config) spellchecker.CheckOptions {
+	return spellchecker.CheckOptions{
+		Exclude:          cfg.Exclude,
+		Include:          cfg.Include,
+		Log:              os.Stderr,
+		Verbose:          cfg.Verbose,
+		CheckStrings:     cfg.CheckStrings,
+		SplitIdentifiers: cfg.SplitIdentifiers,
+		Suggestions: spellchecker.SuggestionOptions{
+			Max:      cfg.MaxSuggestions,
+			Metric:   cfg.Distance,
+			Phonetic: cfg.Phonetic,
+		},
+		ReportUnusedDirectives: cfg.ReportUnusedDirectives,
+		NoIgnore:               cfg.NoIgnore,
+	}
+}
+
+// pathOptions returns the options for checking path. The files the checker writes are
+// excluded from it and, with --diff-base, the check is restricted to the changes of
+// the Git repository of path.
+func pathOptions(cfg *Config, path string, opts spellchecker.CheckOptions) (spellchecker.CheckOptions, error) {
+	// Baseline files are the checker's own data, not text to check.
+	files := []string{cfg.Baseline, cfg.WriteBaseline}
+	if cfg.Watch {
+		// Writing the report must not trigger another check.
+		files = append(files, cfg.Output)
+	}
+	opts.Exclude = slices.Clone(opts.Exclude)
+	for _, file := range files {
+		if pattern, ok := filePattern(path, file); file != "" && ok {
+			opts.Exclude = append(opts.Exclude, pattern)
+		}
+	}
+	if cfg.DiffBase != "" {
+		changes, err := spellchecker.GitChanges(path, cfg.DiffBase)
+		if err != nil {
+			return opts, err
+		}
+		opts.Changes = changes
+		fmt.Fprintf(os.Stderr, "Checking the changed lines of %d files in %s.\n", changes.Len(), path)
+	}
+	return opts, nil
+}
+
+// applyBaseline removes the findings recorded in the baseline file from results, and
+// lists on standard error the entries that no longer occur.
+func applyBaseline(path string, results map[string][]spellchecker.MisspelledWord, checked []string) (map[string][]spellchecker.MisspelledWord, error) {
+	b, err := spellchecker.LoadBaseline(path)
+	if err != nil {
+		return nil, fmt.Errorf("loading baseline: %w", err)
+	}
+	fresh, stale, err := b.Apply(results, checked)
+	if err != nil {
+		return nil, fmt.Errorf("applying baseline: %w", err)
+	}
+	if len(stale) > 0 {
+		fmt.Fprintf(os.Stderr, "%d baseline entries no longer occur; run with --write-baseline %s to remove them:\n", len(stale), path)
+		for _, e := range stale {
+			fmt.Fprintf(os.Stderr, "  %s: \"%s\" (%s)", e.File, e.Word, e.Rule)
+			if e.Count > 1 {
+				fmt.Fprintf(os.Stderr, " x%d", e.Count)
+			}
+			fmt.Fprintln(os.Stderr)
+		}
+	}
+	return fresh, nil
+}
+
+// writeOutput writes the report to standard output, or to the output file or
+// directory of the configuration.
+func writeOutput(cfg *Config, results map[string][]spellchecker.MisspelledWord, summary spellchecker.ScanSummary) error {
+	// The format was validated when the configuration was loaded.
+	format, _ := spellchecker.ReportFormat(cfg.Format, cfg.Output)
+	reporter, _ := spellchecker.NewReporter(format)
+	ext := strings.ToLower(filepath.Ext(cfg.Output))
+	switch {
+	case cfg.Output == "":
+		// No output path provided, so print the report to standard output.
+		if err := reporter.Report(os.Stdout, results, summary); err != nil {
+			return fmt.Errorf("writing report: %w", err)
+		}
+	case format == "html" && ext != ".html":
+		// Multi-file directory mode: the format is HTML but the path does not end in ".html".
+		fmt.Fprintf(os.Stderr, "Generating multi-file HTML report in directory: %s\n", cfg.Output)
+		if err := spellchecker.GenerateMultiFileHTMLReport(cfg.Output, results, summary); err != nil {
+			return fmt.Errorf("generating multi-file report: %w", err)
+		}
+		// One page per file, and the index.
+		fmt.Fprintf(os.Stderr, "Successfully generated %d report files in %s\n", len(results)+1, cfg.Output)
+	default:
+		file, err := os.Create(cfg.Output)
+		if err != nil {
+			return fmt.Errorf("creating output file: %w", err)
+		}
+		defer file.Close()
+
+		fmt.Fprintf(os.Stderr, "Report will be saved to: %s\n", cfg.Output)
+		if err := reporter.Report(file, results, summary); err != nil {
+			return fmt.Errorf("writing report: %w", err)
+		}
+		if err := file.Close(); err != nil {
+			return fmt.Errorf("writing report: %w", err)
+		}
+	}
+	return nil
 }
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// filePattern returns an exclude pattern matching exactly file, for a scan of root.
+// It reports false when file is not inside root.
+func filePattern(root, file string) (string, bool) {
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		return "", false
+	}
+	absFile, err := filepath.Abs(file)
+	if err != nil {
+		return "", false
+	}
+	rel, err := filepath.Rel(absRoot, absFile)
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	escaped := strings.NewReplacer(`\`, `\\`, "*", `\*`, "?", `\?`, "[", `\[`).Replace(filepath.ToSlash(rel))
+	return "/" + escaped, true
+}
+
+// uniquePaths returns paths without duplicates, in order. Paths naming the same file
+// or directory, such as "docs", "./docs/" and its absolute path, are duplicates; the
+// first one is kept as it is written. "-" is kept at most once too.
+func uniquePaths(paths []string) []string {
+	seen := make(map[string]bool, len(paths))
+	var unique []string
+	for _, p := range paths {
+		key := p
+		if p != "-" {
+			if abs, err := filepath.Abs(p); err == nil {
+				key = abs
+			}
+		}
+		if !seen[key] {
+			seen[key] = true
+			unique = append(unique, p)
+		}
+	}
+	return unique
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"testing"
+
+	"spell-checker-cli/spellchecker"
+)
+
+func TestFilePattern(t *testing.T) {
+	root := t.TempDir()
+	testCases := []struct {
+		file    string
+		want    string
+		wantOK  bool
+		matches string
+	}{
+		{filepath.Join(root, "baseline.json"), "/baseline.json", true, "baseline.json"},
+		{filepath.Join(root, "ci", "spell[1].json"), `/ci/spell\[1].json`, true, "ci/spell[1].json"},
+		{filepath.Join(filepath.Dir(root), "outside.json"), "", false, ""},
+		{root, "", false, ""},
+	}
+	for _, tc := range testCases {
+		got, ok := filePattern(root, tc.file)
+		if got != tc.want || ok != tc.wantOK {
+			t.Errorf("filePattern(%q) = %q, %v; want %q, %v", tc.file, got, ok, tc.want, tc.wantOK)
+			continue
+		}
+		if ok && !excludes(t, root, got, tc.matches) {
+			t.Errorf("pattern %q does not exclude %q", got, tc.matches)
+		}
+	}
+}
+
+// excludes reports whether a check of root with the exclude pattern skips the file
+// rel, which it creates.
+func excludes(t *testing.T, root, pattern, rel string) bool {
+	t.Helper()
+	path := filepath.Join(root, filepath.FromSlash(rel))
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("text\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	checker := spellchecker.NewChecker(&spellchecker.Dictionary{}, spellchecker.CheckOptions{Exclude: []string{pattern}})
+	_, summary, err := checker.CheckPath(context.Background(), root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return !slices.Contains(summary.Files, path)
+}
+
+func TestUniquePaths(t *testing.T) {
+	abs, err := filepath.Abs("docs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := uniquePaths([]string{"docs", "-", "./docs/", "README.md", abs, "-", "docs/guide.md"})
+	want := []string{"docs", "-", "README.md", "docs/guide.md"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("uniquePaths() = %q, want %q", got, want)
+	}
+}
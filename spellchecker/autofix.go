@@ -0,0 +1,228 @@
+package spellchecker
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ParseReplacements parses "typo=replacement" pairs into a map keyed by the
+// normalized typo.
+func ParseReplacements(pairs []string) (map[string]string, error) {
+	replacements := make(map[string]string, len(pairs))
+	for _, pair := range pairs {
+		typo, replacement, ok := strings.Cut(pair, "=")
+		typo, replacement = strings.TrimSpace(typo), strings.TrimSpace(replacement)
+		if !ok || typo == "" || replacement == "" || strings.ContainsAny(pair, "\r\n") {
+			return nil, fmt.Errorf("invalid replacement %q (want typo=replacement)", pair)
+		}
+		replacements[normalizeWord(typo)] = replacement
+	}
+	return replacements, nil
+}
+
+// MatchCase returns replacement with the casing of original: upper case for an
+// all-caps word such as "TEH", a capital first letter for a capitalized word such as
+// "Teh", and replacement unchanged otherwise.
+func MatchCase(original, replacement string) string {
+	first, size := utf8.DecodeRuneInString(original)
+	if !unicode.IsUpper(first) {
+		return replacement
+	}
+	rest := original[size:]
+	if strings.IndexFunc(rest, unicode.IsLetter) >= 0 && strings.ToUpper(rest) == rest {
+		return strings.ToUpper(replacement)
+	}
+	if strings.ToLower(rest) != rest {
+		// Mixed case, such as "GitHbu": there is no casing to carry over.
+		return replacement
+	}
+	r, size := utf8.DecodeRuneInString(replacement)
+	return string(unicode.ToUpper(r)) + replacement[size:]
+}
+
+// confidentSuggestion returns the suggestion for typo that is safe to apply without
+// review: the best one, if it is a single edit away and no other suggestion is.
+func confidentSuggestion(typo MisspelledWord, dictionary *Dictionary, opts SuggestionOptions) (string, bool) {
+	suggestions := typo.Suggestions
+	if opts.Max == 1 && len(suggestions) == 1 {
+		// A second suggestion as close as the first may have been cut off.
+		opts.Max = 2
+		suggestions = generateSuggestions(typo.Word, dictionary, opts)
+	}
+	if len(suggestions) == 0 {
+		return "", false
+	}
+	distance, err := distanceFunc(opts.Metric)
+	if err != nil {
+		distance = osaDistance
+	}
+	word := normalizeWord(typo.Word)
+	// Suggestions are ranked by distance first, so the second one is the closest rival.
+	if distance(word, suggestions[0]) != 1 || (len(suggestions) > 1 && distance(word, suggestions[1]) <= 1) {
+		return "", false
+	}
+	return suggestions[0], true
+}
+
+// PlanAutoFixes returns the edits --fix makes to results, and the typos it leaves as
+// they are. A typo is fixed when the replacement map names it, or else when it has a
+// confident suggestion; the replacement takes the casing of the typo.
+func PlanAutoFixes(results map[string][]MisspelledWord, dictionary *Dictionary, replacements map[string]string, opts SuggestionOptions) (map[string][]FixEdit, map[string][]MisspelledWord) {
+	edits := make(map[string][]FixEdit)
+	remaining := make(map[string][]MisspelledWord)
+	for path, typos := range results {
+		for _, typo := range typos {
+			if typo.rule() == RuleUnusedDirective {
+				remaining[path] = append(remaining[path], typo)
+				continue
+			}
+			replacement, ok := replacements[normalizeWord(typo.Word)]
+			if !ok {
+				replacement, ok = confidentSuggestion(typo, dictionary, opts)
+			}
+			if !ok {
+				remaining[path] = append(remaining[path], typo)
+				continue
+			}
+			edits[path] = append(edits[path], FixEdit{
+				Line:   typo.LineNumber,
+				Column: typo.Column,
+				Old:    typo.Word,
+				New:    MatchCase(typo.Word, replacement),
+			})
+		}
+	}
+	return edits, remaining
+}
+
+// ApplyAutoFixes applies edits to their files, or, when w is not nil, leaves the files
+// untouched and writes the changes to w as a unified diff instead.
+func ApplyAutoFixes(edits map[string][]FixEdit, w io.Writer) error {
+	paths := make([]string, 0, len(edits))
+	for path := range edits {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		content, err := applyEdits(string(data), edits[path])
+		if err != nil {
+			return fmt.Errorf("fixing %s: %w", path, err)
+		}
+		if w != nil {
+			if _, err := io.WriteString(w, unifiedDiff(path, string(data), content)); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := writeFileAtomic(path, []byte(content)); err != nil {
+			return fmt.Errorf("fixing %s: %w", path, err)
+		}
+	}
+	return nil
+}
+
+// diffContext is the number of unchanged lines shown around changes in a diff.
+const diffContext = 3
+
+// unifiedDiff returns the changes from before to after as a unified diff of the file
+// path, in the form "git apply" and "patch -p1" accept. Fixes replace words within
+// lines, so lines are compared one to one; should the number of lines differ, the
+// whole file is shown as replaced.
+func unifiedDiff(path, before, after string) string {
+	if before == after {
+		return ""
+	}
+	a, b := diffLines(before), diffLines(after)
+	var out strings.Builder
+	name := filepath.ToSlash(filepath.Clean(path))
+	fmt.Fprintf(&out, "--- a/%s\n+++ b/%s\n", name, name)
+	if len(a) != len(b) {
+		fmt.Fprintf(&out, "@@ -%s +%s @@\n", hunkRange(len(a)), hunkRange(len(b)))
+		for _, line := range a {
+			writeDiffLine(&out, "-", line)
+		}
+		for _, line := range b {
+			writeDiffLine(&out, "+", line)
+		}
+		return out.String()
+	}
+
+	var changed []int
+	for i := range a {
+		if a[i] != b[i] {
+			changed = append(changed, i)
+		}
+	}
+	for len(changed) > 0 {
+		// A hunk extends while the context of the next change overlaps its own.
+		start := max(changed[0]-diffContext, 0)
+		last := 0
+		for last+1 < len(changed) && changed[last+1]-changed[last] <= 2*diffContext+1 {
+			last++
+		}
+		end := changed[last] + diffContext + 1
+		if end > len(a) {
+			end = len(a)
+		}
+		changed = changed[last+1:]
+
+		fmt.Fprintf(&out, "@@ -%d,%d +%d,%d @@\n", start+1, end-start, start+1, end-start)
+		for i := start; i < end; {
+			if a[i] == b[i] {
+				writeDiffLine(&out, " ", a[i])
+				i++
+				continue
+			}
+			// A run of changed lines is shown as all its removals, then all its additions.
+			j := i
+			for j < end && a[j] != b[j] {
+				j++
+			}
+			for k := i; k < j; k++ {
+				writeDiffLine(&out, "-", a[k])
+			}
+			for k := i; k < j; k++ {
+				writeDiffLine(&out, "+", b[k])
+			}
+			i = j
+		}
+	}
+	return out.String()
+}
+
+// hunkRange formats the range of a hunk covering a whole file of n lines.
+func hunkRange(n int) string {
+	if n == 0 {
+		return "0,0"
+	}
+	return fmt.Sprintf("1,%d", n)
+}
+
+// diffLines splits content into lines that keep their line endings.
+func diffLines(content string) []string {
+	lines := strings.SplitAfter(content, "\n")
+	if lines[len(lines)-1] == "" {
+		// The content ends with a newline, or is empty.
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+func writeDiffLine(out *strings.Builder, prefix, line string) {
+	out.WriteString(prefix)
+	out.WriteString(line)
+	if !strings.HasSuffix(line, "\n") {
+		out.WriteString("\n\\ No newline at end of file\n")
+	}
+}
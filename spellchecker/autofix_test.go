@@ -0,0 +1,141 @@
+package spellchecker
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseReplacements(t *testing.T) {
+	got, err := ParseReplacements([]string{"recieve=receive", " Teh = the ", "alot=a lot"})
+	if err != nil {
+		t.Fatalf("ParseReplacements() error = %v", err)
+	}
+	want := map[string]string{"recieve": "receive", "teh": "the", "alot": "a lot"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseReplacements() = %v, want %v", got, want)
+	}
+	for _, pair := range []string{"recieve", "=receive", "recieve=", "a=b\nc"} {
+		if _, err := ParseReplacements([]string{pair}); err == nil {
+			t.Errorf("ParseReplacements(%q) error = nil, want an error", pair)
+		}
+	}
+}
+
+func TestMatchCase(t *testing.T) {
+	testCases := []struct {
+		original, replacement, want string
+	}{
+		{"teh", "the", "the"},
+		{"Teh", "the", "The"},
+		{"TEH", "the", "THE"},
+		{"I", "i", "I"},
+		{"Écrit", "écrire", "Écrire"},
+		{"GitHbu", "GitHub", "GitHub"},
+		{"githbu", "GitHub", "GitHub"},
+		{"Recieve", "receive", "Receive"},
+	}
+	for _, tc := range testCases {
+		if got := MatchCase(tc.original, tc.replacement); got != tc.want {
+			t.Errorf("MatchCase(%q, %q) = %q, want %q", tc.original, tc.replacement, got, tc.want)
+		}
+	}
+}
+
+func TestConfidentSuggestion(t *testing.T) {
+	dictionary := &Dictionary{Words: map[string]struct{}{"world": {}, "word": {}, "the": {}, "cat": {}, "cut": {}, "cot": {}}}
+	testCases := []struct {
+		name   string
+		typo   MisspelledWord
+		opts   SuggestionOptions
+		want   string
+		wantOK bool
+	}{
+		{"single close suggestion", MisspelledWord{Word: "wrld", Suggestions: []string{"world", "word"}}, SuggestionOptions{}, "world", true},
+		{"swap counts as one edit", MisspelledWord{Word: "Teh", Suggestions: []string{"the"}}, SuggestionOptions{}, "the", true},
+		{"swap is two edits for levenshtein", MisspelledWord{Word: "teh", Suggestions: []string{"the"}}, SuggestionOptions{Metric: MetricLevenshtein}, "", false},
+		{"two suggestions as close", MisspelledWord{Word: "cxt", Suggestions: []string{"cat", "cot", "cut"}}, SuggestionOptions{}, "", false},
+		{"hidden rival", MisspelledWord{Word: "cxt", Suggestions: []string{"cat"}}, SuggestionOptions{Max: 1}, "", false},
+		{"missing letter", MisspelledWord{Word: "wrd", Suggestions: []string{"word"}}, SuggestionOptions{}, "word", true},
+		{"two edits away", MisspelledWord{Word: "wdrl", Suggestions: []string{"world"}}, SuggestionOptions{Metric: MetricLevenshtein}, "", false},
+		{"no suggestion", MisspelledWord{Word: "qwzx"}, SuggestionOptions{}, "", false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, ok := confidentSuggestion(tc.typo, dictionary, tc.opts)
+			if got != tc.want || ok != tc.wantOK {
+				t.Errorf("confidentSuggestion() = %q, %v; want %q, %v", got, ok, tc.want, tc.wantOK)
+			}
+		})
+	}
+}
+
+func TestAutoFix(t *testing.T) {
+	dictionary := &Dictionary{Words: map[string]struct{}{"the": {}, "receive": {}, "cat": {}, "cot": {}}}
+	path := filepath.Join(t.TempDir(), "notes.txt")
+	content := "Teh cat\nline 2\nline 3\nline 4\nline 5\nline 6\nline 7\nline 8\nRECIEVE a cxt\nlast"
+	os.WriteFile(path, []byte(content), 0644)
+
+	results := map[string][]MisspelledWord{path: {
+		{Word: "Teh", LineNumber: 1, Column: 1, Suggestions: []string{"the"}},
+		{Word: "RECIEVE", LineNumber: 9, Column: 1, Suggestions: []string{"receive"}},
+		{Word: "cxt", LineNumber: 9, Column: 11, Suggestions: []string{"cat", "cot"}},
+		{Word: "spellchecker:disable", LineNumber: 10, Column: 1, Rule: RuleUnusedDirective},
+	}}
+	replacements := map[string]string{"recieve": "receive"}
+	edits, remaining := PlanAutoFixes(results, dictionary, replacements, SuggestionOptions{})
+	wantEdits := map[string][]FixEdit{path: {
+		{Line: 1, Column: 1, Old: "Teh", New: "The"},
+		{Line: 9, Column: 1, Old: "RECIEVE", New: "RECEIVE"},
+	}}
+	if !reflect.DeepEqual(edits, wantEdits) {
+		t.Errorf("PlanAutoFixes() edits = %v, want %v", edits, wantEdits)
+	}
+	wantRemaining := map[string][]MisspelledWord{path: {results[path][2], results[path][3]}}
+	if !reflect.DeepEqual(remaining, wantRemaining) {
+		t.Errorf("PlanAutoFixes() remaining = %v, want %v", remaining, wantRemaining)
+	}
+
+	// A dry run prints a diff and leaves the file as it is.
+	var diff bytes.Buffer
+	if err := ApplyAutoFixes(edits, &diff); err != nil {
+		t.Fatalf("ApplyAutoFixes(dry run) error = %v", err)
+	}
+	name := filepath.ToSlash(path)
+	wantDiff := "--- a/" + name + "\n+++ b/" + name + "\n" +
+		"@@ -1,4 +1,4 @@\n-Teh cat\n+The cat\n line 2\n line 3\n line 4\n" +
+		"@@ -6,5 +6,5 @@\n line 6\n line 7\n line 8\n-RECIEVE a cxt\n+RECEIVE a cxt\n last\n\\ No newline at end of file\n"
+	if diff.String() != wantDiff {
+		t.Errorf("dry run diff = %q, want %q", diff.String(), wantDiff)
+	}
+	if got, _ := os.ReadFile(path); string(got) != content {
+		t.Errorf("dry run changed the file to %q", got)
+	}
+
+	if err := ApplyAutoFixes(edits, nil); err != nil {
+		t.Fatalf("ApplyAutoFixes() error = %v", err)
+	}
+	want := "The cat\nline 2\nline 3\nline 4\nline 5\nline 6\nline 7\nline 8\nRECEIVE a cxt\nlast"
+	if got, _ := os.ReadFile(path); string(got) != want {
+		t.Errorf("fixed file = %q, want %q", got, want)
+	}
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	if got := unifiedDiff("a.txt", "same\n", "same\n"); got != "" {
+		t.Errorf("unifiedDiff() of equal contents = %q, want \"\"", got)
+	}
+	// Adjacent changed lines form one run; CRLF endings are kept.
+	got := unifiedDiff("a.txt", "one\r\ntwo\r\nthree\r\n", "1\r\n2\r\nthree\r\n")
+	want := "--- a/a.txt\n+++ b/a.txt\n@@ -1,3 +1,3 @@\n-one\r\n-two\r\n+1\r\n+2\r\n three\r\n"
+	if got != want {
+		t.Errorf("unifiedDiff() = %q, want %q", got, want)
+	}
+	got = unifiedDiff("a.txt", "one\n", "one\ntwo\n")
+	want = "--- a/a.txt\n+++ b/a.txt\n@@ -1,1 +1,2 @@\n-one\n+one\n+two\n"
+	if got != want {
+		t.Errorf("unifiedDiff() with added lines = %q, want %q", got, want)
+	}
+}
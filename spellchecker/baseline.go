@@ -0,0 +1,215 @@
+package spellchecker
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// baselineVersion is the version of the baseline file format.
+const baselineVersion = 1
+
+// Baseline records known findings so that only new ones are reported. Entries are
+// keyed by file, rule, word and a fingerprint of the content of the line, not by line
+// number, so that a finding still matches its entry when lines are added or removed
+// above it.
+type Baseline struct {
+	// Version is the version of the file format.
+	Version int `json:"version"`
+	// Entries are the known findings, sorted by file, word, rule and fingerprint.
+	Entries []BaselineEntry `json:"entries"`
+	// dir is the directory of the baseline file, which entry files are relative to.
+	dir string
+}
+
+// BaselineEntry records the known findings of one word on one line of a file.
+type BaselineEntry struct {
+	// File is the slash-separated path of the file, relative to the baseline file.
+	File string `json:"file"`
+	// Word is the misspelled word as it appears in the file.
+	Word string `json:"word"`
+	// Rule is the rule that reported the word, see MisspelledWord.
+	Rule string `json:"rule"`
+	// Fingerprint is a hash of the line containing the finding, see lineFingerprint.
+	Fingerprint string `json:"fingerprint"`
+	// Count is the number of identical findings, e.g. a word repeated on a line.
+	Count int `json:"count"`
+}
+
+type baselineKey struct {
+	file, rule, word, fingerprint string
+}
+
+func (e BaselineEntry) key() baselineKey {
+	return baselineKey{e.File, e.Rule, e.Word, e.Fingerprint}
+}
+
+// NewBaseline returns a baseline of all the findings in results, to be written to path.
+func NewBaseline(path string, results map[string][]MisspelledWord) (*Baseline, error) {
+	b := &Baseline{Version: baselineVersion, Entries: []BaselineEntry{}, dir: filepath.Dir(path)}
+	counts := make(map[baselineKey]int)
+	for file, typos := range results {
+		keys, err := b.keys(file, typos)
+		if err != nil {
+			return nil, err
+		}
+		for _, key := range keys {
+			counts[key]++
+		}
+	}
+	for key, count := range counts {
+		b.Entries = append(b.Entries, BaselineEntry{File: key.file, Word: key.word, Rule: key.rule, Fingerprint: key.fingerprint, Count: count})
+	}
+	b.sort()
+	return b, nil
+}
+
+// LoadBaseline reads a baseline file written by Baseline.Write.
+func LoadBaseline(path string) (*Baseline, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	b := &Baseline{dir: filepath.Dir(path)}
+	if err := json.Unmarshal(data, b); err != nil {
+		return nil, fmt.Errorf("reading baseline %s: %w", path, err)
+	}
+	if b.Version != baselineVersion {
+		return nil, fmt.Errorf("reading baseline %s: unsupported version %d", path, b.Version)
+	}
+	return b, nil
+}
+
+// Write saves the baseline to path as indented JSON, sorted so that it diffs well
+// under version control.
+func (b *Baseline) Write(path string) error {
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// Size returns the number of findings recorded in the baseline.
+func (b *Baseline) Size() int {
+	n := 0
+	for _, e := range b.Entries {
+		n += e.Count
+	}
+	return n
+}
+
+// Apply removes the findings recorded in the baseline from results. It returns the new
+// findings, and the stale entries: those that no longer occur in a file that was
+// checked, or in a file that no longer exists. Entries of other files, e.g. excluded
+// ones, are neither matched nor stale.
+func (b *Baseline) Apply(results map[string][]MisspelledWord, checked []string) (map[string][]MisspelledWord, []BaselineEntry, error) {
+	remaining := make(map[baselineKey]int)
+	for _, e := range b.Entries {
+		remaining[e.key()] += e.Count
+	}
+
+	fresh := make(map[string][]MisspelledWord)
+	for file, typos := range results {
+		keys, err := b.keys(file, typos)
+		if err != nil {
+			return nil, nil, err
+		}
+		for i, key := range keys {
+			if remaining[key] > 0 {
+				remaining[key]--
+				continue
+			}
+			fresh[file] = append(fresh[file], typos[i])
+		}
+	}
+
+	checkedFiles := make(map[string]bool, len(checked))
+	for _, file := range checked {
+		checkedFiles[b.relative(file)] = true
+	}
+	var stale []BaselineEntry
+	for _, e := range b.Entries {
+		left := remaining[e.key()]
+		if left > e.Count {
+			left = e.Count
+		}
+		if left == 0 || (!checkedFiles[e.File] && !b.missing(e.File)) {
+			continue
+		}
+		remaining[e.key()] -= left
+		e.Count = left
+		stale = append(stale, e)
+	}
+	return fresh, stale, nil
+}
+
+// keys returns the baseline key of each finding of a file.
+func (b *Baseline) keys(file string, typos []MisspelledWord) ([]baselineKey, error) {
+	lines, err := readLines(file)
+	if err != nil && lines == nil {
+		return nil, err
+	}
+	rel := b.relative(file)
+	keys := make([]baselineKey, len(typos))
+	for i, typo := range typos {
+		var line string
+		if typo.LineNumber >= 1 && typo.LineNumber <= len(lines) {
+			line = lines[typo.LineNumber-1]
+		}
+		keys[i] = baselineKey{rel, typo.rule(), typo.Word, lineFingerprint(line)}
+	}
+	return keys, nil
+}
+
+// missing reports whether the file of an entry no longer exists.
+func (b *Baseline) missing(file string) bool {
+	_, err := os.Stat(filepath.Join(b.dir, filepath.FromSlash(file)))
+	return os.IsNotExist(err)
+}
+
+// relative returns the path of a checked file relative to the baseline file,
+// slash-separated, so that a baseline does not depend on the working directory.
+func (b *Baseline) relative(file string) string {
+	abs, err := filepath.Abs(file)
+	if err != nil {
+		return filepath.ToSlash(file)
+	}
+	dir, err := filepath.Abs(b.dir)
+	if err != nil {
+		return filepath.ToSlash(file)
+	}
+	rel, err := filepath.Rel(dir, abs)
+	if err != nil {
+		return filepath.ToSlash(abs)
+	}
+	return filepath.ToSlash(rel)
+}
+
+func (b *Baseline) sort() {
+	sort.Slice(b.Entries, func(i, j int) bool {
+		x, y := b.Entries[i], b.Entries[j]
+		if x.File != y.File {
+			return x.File < y.File
+		}
+		if x.Word != y.Word {
+			return x.Word < y.Word
+		}
+		if x.Rule != y.Rule {
+			return x.Rule < y.Rule
+		}
+		return x.Fingerprint < y.Fingerprint
+	})
+}
+
+// lineFingerprint hashes the content of a line, ignoring leading and trailing white
+// space so that reindenting a line keeps its findings in the baseline.
+func lineFingerprint(line string) string {
+	sum := sha256.Sum256([]byte(strings.TrimSpace(line)))
+	return hex.EncodeToString(sum[:8])
+}
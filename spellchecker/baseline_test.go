@@ -0,0 +1,98 @@
+package spellchecker
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestBaseline(t *testing.T) {
+	dir := t.TempDir()
+	notes := createFile(t, dir, "docs/notes.txt", "teh first line\nteh teh\n")
+	gone := createFile(t, dir, "gone.txt", "wrld\n")
+	other := createFile(t, dir, "other.txt", "wrld\n")
+	baselinePath := filepath.Join(dir, "spellcheck-baseline.json")
+
+	results := map[string][]MisspelledWord{
+		notes: {
+			{Word: "teh", LineNumber: 1, Column: 1},
+			{Word: "teh", LineNumber: 2, Column: 1},
+			{Word: "teh", LineNumber: 2, Column: 5},
+		},
+		gone:  {{Word: "wrld", LineNumber: 1, Column: 1}},
+		other: {{Word: "wrld", LineNumber: 1, Column: 1}},
+	}
+	b, err := NewBaseline(baselinePath, results)
+	if err != nil {
+		t.Fatalf("NewBaseline() error = %v", err)
+	}
+	if err := b.Write(baselinePath); err != nil {
+		t.Fatalf("write() error = %v", err)
+	}
+	if b.Size() != 5 {
+		t.Errorf("size() = %d, want 5", b.Size())
+	}
+	b, err = LoadBaseline(baselinePath)
+	if err != nil {
+		t.Fatalf("LoadBaseline() error = %v", err)
+	}
+	wantEntries := []BaselineEntry{
+		{File: "docs/notes.txt", Word: "teh", Rule: RuleUnknownWord, Fingerprint: lineFingerprint("teh first line"), Count: 1},
+		{File: "docs/notes.txt", Word: "teh", Rule: RuleUnknownWord, Fingerprint: lineFingerprint("teh teh"), Count: 2},
+		{File: "gone.txt", Word: "wrld", Rule: RuleUnknownWord, Fingerprint: lineFingerprint("wrld"), Count: 1},
+		{File: "other.txt", Word: "wrld", Rule: RuleUnknownWord, Fingerprint: lineFingerprint("wrld"), Count: 1},
+	}
+	if len(b.Entries) != len(wantEntries) {
+		t.Fatalf("loaded entries = %v, want %v", b.Entries, wantEntries)
+	}
+	for i := range wantEntries {
+		if b.Entries[i] != wantEntries[i] {
+			t.Errorf("entry %d = %+v, want %+v", i, b.Entries[i], wantEntries[i])
+		}
+	}
+
+	// Lines are shifted and reindented, one occurrence is fixed, a typo is added, and
+	// gone.txt is deleted. other.txt was not checked this time, so its entry is kept.
+	createFile(t, dir, "docs/notes.txt", "new line\n  teh first line\nteh tpyo\n")
+	os.Remove(gone)
+	results = map[string][]MisspelledWord{
+		notes: {
+			{Word: "teh", LineNumber: 2, Column: 3},
+			{Word: "teh", LineNumber: 3, Column: 1},
+			{Word: "tpyo", LineNumber: 3, Column: 5},
+		},
+	}
+	fresh, stale, err := b.Apply(results, []string{notes})
+	if err != nil {
+		t.Fatalf("apply() error = %v", err)
+	}
+	wantFresh := map[string][]MisspelledWord{
+		notes: {
+			{Word: "teh", LineNumber: 3, Column: 1},
+			{Word: "tpyo", LineNumber: 3, Column: 5},
+		},
+	}
+	if !reflect.DeepEqual(fresh, wantFresh) {
+		t.Errorf("apply() findings = %v, want %v", fresh, wantFresh)
+	}
+	wantStale := []BaselineEntry{
+		{File: "docs/notes.txt", Word: "teh", Rule: RuleUnknownWord, Fingerprint: lineFingerprint("teh teh"), Count: 2},
+		{File: "gone.txt", Word: "wrld", Rule: RuleUnknownWord, Fingerprint: lineFingerprint("wrld"), Count: 1},
+	}
+	if !reflect.DeepEqual(stale, wantStale) {
+		t.Errorf("apply() stale = %v, want %v", stale, wantStale)
+	}
+}
+
+func TestLoadBaselineErrors(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := LoadBaseline(filepath.Join(dir, "missing.json")); err == nil {
+		t.Error("LoadBaseline() error = nil, want an error for a missing file")
+	}
+	path := filepath.Join(dir, "baseline.json")
+	os.WriteFile(path, []byte(`{"version": 2, "entries": []}`), 0644)
+	if _, err := LoadBaseline(path); err == nil {
+		t.Error("LoadBaseline() error = nil, want an error for an unsupported version")
+	}
+}
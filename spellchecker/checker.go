@@ -0,0 +1,481 @@
+package spellchecker
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// MisspelledWord is a word missing from the dictionary, at a 1-based line and rune
+// column, with its suggested corrections.
+type MisspelledWord struct {
+	Word        string
+	LineNumber  int
+	Column      int
+	Suggestions []string
+	// Rule identifies the check that reported the word; empty means RuleUnknownWord.
+	Rule string
+}
+
+// Rule identifiers name the check that reported a word, in reports that distinguish them.
+const (
+	// RuleUnknownWord reports words missing from the dictionary.
+	RuleUnknownWord = "unknown-word"
+	// RuleForbiddenWord reports words the dictionary explicitly forbids.
+	RuleForbiddenWord = "forbidden-word"
+	// RuleUnusedDirective reports inline directives that suppress nothing.
+	RuleUnusedDirective = "unused-directive"
+)
+
+// rule returns the identifier of the check that reported m.
+func (m MisspelledWord) rule() string {
+	if m.Rule == "" {
+		return RuleUnknownWord
+	}
+	return m.Rule
+}
+
+// Message describes m in one sentence, with its suggestions, as the text report does.
+func (m MisspelledWord) Message() string {
+	return typoMessage(m)
+}
+
+// CheckResult holds the findings of one file.
+type CheckResult struct {
+	FilePath string
+	Typos    []MisspelledWord
+	// Err is the error that kept the file from being read to the end, if any; Typos
+	// are those found before it.
+	Err error
+}
+
+// FileError is an error that kept a file or directory from being checked, such as a
+// permission error or a line too long to read.
+type FileError struct {
+	Path string
+	Err  error
+}
+
+// Error returns the message of the error, which names the path.
+func (e FileError) Error() string {
+	var pathErr *fs.PathError
+	if errors.As(e.Err, &pathErr) && pathErr.Path == e.Path {
+		return e.Err.Error()
+	}
+	return e.Path + ": " + e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e FileError) Unwrap() error {
+	return e.Err
+}
+
+// CheckOptions controls which files are scanned and how their contents are checked.
+type CheckOptions struct {
+	// Exclude is a list of glob patterns for files and directories to skip, and Include,
+	// when not empty, a list of glob patterns for the only files to check. See pathFilter.
+	Exclude []string
+	Include []string
+	// Log, when not nil, receives progress messages, such as those of Watch. Nil
+	// discards them.
+	Log io.Writer
+	// Verbose also logs the skipped files and directories to Log.
+	Verbose bool
+	// CheckStrings also checks string literals in source files, not only comments.
+	CheckStrings bool
+	// SplitIdentifiers checks the parts of camelCase and PascalCase words separately
+	// when the whole word is not in the dictionary.
+	SplitIdentifiers bool
+	// Suggestions controls the suggestions offered for each misspelled word.
+	Suggestions SuggestionOptions
+	// ReportUnusedDirectives reports inline directives that suppress no typo.
+	ReportUnusedDirectives bool
+	// NoIgnore disables .gitignore, .git/info/exclude and .spellcheckignore files.
+	// .git directories are skipped either way.
+	NoIgnore bool
+	// Changes, when not nil, restricts the check to the changed files of a diff and
+	// the report to the typos on changed lines.
+	Changes *ChangeSet
+}
+
+// ScanSummary describes a whole run of the checker.
+type ScanSummary struct {
+	// FilesScanned is the number of files that were checked.
+	FilesScanned int
+	// FilesSkipped is the number of files skipped because they were excluded, not
+	// included, ignored or binary. Files inside skipped directories and files left
+	// unchanged by the diff of CheckOptions.Changes are not counted.
+	FilesSkipped int
+	// DictionarySize is the number of words accepted by the dictionary.
+	DictionarySize int
+	// Files lists the checked files, sorted, including those without typos. Files
+	// that could not be read to the end are listed in Errors instead.
+	Files []string
+	// Errors lists the files and directories that could not be checked, sorted by
+	// path. The typos found in a file before an error are still reported.
+	Errors []FileError
+}
+
+// runConcurrentChecker checks the files of rootPath on all CPUs. Errors reading a file
+// or directory do not stop the scan; they are collected in the summary. When ctx is
+// done, the scan stops and returns the results so far with the context's error.
+func runConcurrentChecker(ctx context.Context, rootPath string, dictionary *Dictionary, opts CheckOptions) (map[string][]MisspelledWord, ScanSummary, error) {
+	summary := ScanSummary{DictionarySize: len(dictionary.Words)}
+	jobs := make(chan string, 100)
+	results := make(chan CheckResult, 100)
+	var wg sync.WaitGroup
+
+	numWorkers := runtime.NumCPU()
+	for i := 0; i < numWorkers; i++ {
+		wg.Add(1)
+		go worker(ctx, &wg, jobs, results, dictionary, opts)
+	}
+
+	go func() {
+		defer close(jobs)
+		filter := newPathFilter(rootPath, opts.Include, opts.Exclude)
+		// Ignore files only apply to the contents of a directory; a file named on the
+		// command line is always checked.
+		var ignores *ignoreMatcher
+		if info, err := os.Stat(rootPath); err == nil && info.IsDir() && !opts.NoIgnore {
+			if ignores, err = newIgnoreMatcher(rootPath); err != nil {
+				summary.Errors = append(summary.Errors, FileError{Path: rootPath, Err: err})
+				ignores = nil
+			}
+		}
+
+		filepath.Walk(rootPath, func(path string, info os.FileInfo, err error) error {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return ctxErr
+			}
+			if err != nil {
+				// An unreadable directory is skipped, the rest of the tree still checked.
+				summary.Errors = append(summary.Errors, FileError{Path: path, Err: err})
+				return nil
+			}
+
+			if info.IsDir() {
+				if path != rootPath && info.Name() == ".git" {
+					if opts.Verbose {
+						opts.logf("Skipping Git directory: %s\n", path)
+					}
+					return filepath.SkipDir
+				}
+				if path != rootPath && ignores != nil && ignores.ignored(path, true) {
+					if opts.Verbose {
+						opts.logf("Skipping ignored directory: %s\n", path)
+					}
+					return filepath.SkipDir
+				}
+
+				if path != rootPath && filter.excluded(path, true) {
+					// --- IMPROVEMENT: Conditionally print skipped directory ---
+					if opts.Verbose {
+						opts.logf("Skipping excluded directory: %s\n", path)
+					}
+					return filepath.SkipDir
+				}
+				if path != rootPath && opts.Changes != nil && !opts.Changes.hasDir(path) {
+					if opts.Verbose {
+						opts.logf("Skipping unchanged directory: %s\n", path)
+					}
+					return filepath.SkipDir
+				}
+				if ignores != nil {
+					if err := ignores.loadDir(path); err != nil {
+						summary.Errors = append(summary.Errors, FileError{Path: path, Err: err})
+					}
+				}
+				return nil
+			}
+
+			if filter.excluded(path, false) {
+				summary.FilesSkipped++
+				if opts.Verbose {
+					opts.logf("Skipping excluded file: %s\n", path)
+				}
+				return nil
+			}
+			if !filter.included(path) {
+				summary.FilesSkipped++
+				if opts.Verbose {
+					opts.logf("Skipping file not included: %s\n", path)
+				}
+				return nil
+			}
+			if opts.Changes != nil && !opts.Changes.hasFile(path) {
+				if opts.Verbose {
+					opts.logf("Skipping unchanged file: %s\n", path)
+				}
+				return nil
+			}
+			if path != rootPath && ignores != nil && ignores.ignored(path, false) {
+				summary.FilesSkipped++
+				if opts.Verbose {
+					opts.logf("Skipping ignored file: %s\n", path)
+				}
+				return nil
+			}
+
+			isBinary, err := isLikelyBinary(path)
+			if err != nil {
+				summary.Errors = append(summary.Errors, FileError{Path: path, Err: err})
+				return nil
+			}
+			if isBinary {
+				summary.FilesSkipped++
+				if opts.Verbose {
+					opts.logf("Skipping binary file: %s\n", path)
+				}
+				return nil
+			}
+			jobs <- path
+			return nil
+		})
+	}()
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	allTypos := make(map[string][]MisspelledWord)
+	var fileErrors []FileError
+	for result := range results {
+		if result.Err != nil {
+			fileErrors = append(fileErrors, FileError{Path: result.FilePath, Err: result.Err})
+		} else {
+			summary.Files = append(summary.Files, result.FilePath)
+		}
+		if len(result.Typos) > 0 {
+			allTypos[result.FilePath] = result.Typos
+		}
+	}
+	// The walk has finished once results is closed, so summary.FilesSkipped and the
+	// errors of the walk are final.
+	summary.Errors = append(summary.Errors, fileErrors...)
+	sortFileErrors(summary.Errors)
+	sort.Strings(summary.Files)
+	summary.FilesScanned = len(summary.Files)
+	return allTypos, summary, ctx.Err()
+}
+
+// logf writes a progress message to o.Log, if set.
+func (o CheckOptions) logf(format string, args ...any) {
+	if o.Log != nil {
+		fmt.Fprintf(o.Log, format, args...)
+	}
+}
+
+// Merge adds the files of another scan to the summary. A file checked by both scans,
+// as when paths overlap, is counted once.
+func (s *ScanSummary) Merge(other ScanSummary) {
+	s.FilesSkipped += other.FilesSkipped
+	s.Files = append(s.Files, other.Files...)
+	sort.Strings(s.Files)
+	s.Files = slices.Compact(s.Files)
+	s.FilesScanned = len(s.Files)
+	s.Errors = append(s.Errors, other.Errors...)
+	sortFileErrors(s.Errors)
+	s.Errors = slices.CompactFunc(s.Errors, func(a, b FileError) bool {
+		return a.Path == b.Path && a.Err.Error() == b.Err.Error()
+	})
+}
+
+// sortFileErrors sorts errors by path, keeping the order of the errors of a path.
+func sortFileErrors(list []FileError) {
+	sort.SliceStable(list, func(i, j int) bool {
+		return list[i].Path < list[j].Path
+	})
+}
+
+// worker checks the files sent on jobs. Once ctx is done, the remaining jobs are
+// drained without being checked.
+func worker(ctx context.Context, wg *sync.WaitGroup, jobs <-chan string, results chan<- CheckResult, dictionary *Dictionary, opts CheckOptions) {
+	defer wg.Done()
+	for path := range jobs {
+		if ctx.Err() != nil {
+			continue
+		}
+		typos, err := checkFile(path, dictionary, opts)
+		if opts.Changes != nil {
+			typos = opts.Changes.filter(path, typos)
+		}
+		results <- CheckResult{FilePath: path, Typos: typos, Err: err}
+	}
+}
+
+// textExtractor rewrites the lines of a file so that only the text worth spell checking
+// remains. Everything else must be replaced by spaces rune-for-rune, which keeps the
+// line and column of every remaining word identical to the original file.
+type textExtractor func(lines []string) []string
+
+// extractors maps a lowercase file extension to the extractor used for that file type.
+// Source files are handled by codeSyntaxes; files matching neither are checked as plain text.
+var extractors = map[string]textExtractor{
+	".md":       extractMarkdownProse,
+	".markdown": extractMarkdownProse,
+}
+
+// commentExtractors maps a lowercase file extension to the extractor that keeps only the
+// comments of that file type; source files use their codeSyntaxes.
+var commentExtractors = map[string]textExtractor{
+	".md":       extractMarkdownComments,
+	".markdown": extractMarkdownComments,
+}
+
+// extractComments keeps only the comments of a document, the only place where inline
+// directives are recognized: HTML comments in Markdown, line and block comments in
+// source code. Plain text has no comment syntax, so there a line that starts with a
+// directive stands for one, see extractDirectiveLines.
+func extractComments(filePath string, lines []string) []string {
+	ext := strings.ToLower(filepath.Ext(filePath))
+	if extract, ok := commentExtractors[ext]; ok {
+		return extract(lines)
+	}
+	if syntax, ok := codeSyntaxes[ext]; ok {
+		return syntax.extractor(false)(lines)
+	}
+	if syntax, ok := codeSyntaxByName[filepath.Base(filePath)]; ok {
+		return syntax.extractor(false)(lines)
+	}
+	return extractDirectiveLines(lines)
+}
+
+// extractorFor returns the extractor for the file's type, or nil for plain text.
+func extractorFor(filePath string, opts CheckOptions) textExtractor {
+	ext := strings.ToLower(filepath.Ext(filePath))
+	if extract, ok := extractors[ext]; ok {
+		return extract
+	}
+	if syntax, ok := codeSyntaxes[ext]; ok {
+		return syntax.extractor(opts.CheckStrings)
+	}
+	if syntax, ok := codeSyntaxByName[filepath.Base(filePath)]; ok {
+		return syntax.extractor(opts.CheckStrings)
+	}
+	return nil
+}
+
+// checkFile checks a file. A read error, such as a line too long to scan, is returned
+// with the typos of the lines before it.
+func checkFile(filePath string, dictionary *Dictionary, opts CheckOptions) ([]MisspelledWord, error) {
+	lines, err := readLines(filePath)
+	if lines == nil {
+		return nil, err
+	}
+	return checkLines(filePath, lines, dictionary, opts), err
+}
+
+// checkLines checks the lines of a document, such as a file or an editor buffer.
+// filePath only selects the extractor for the document's type. lines is modified.
+func checkLines(filePath string, lines []string, dictionary *Dictionary, opts CheckOptions) []MisspelledWord {
+	directives := parseDirectives(extractComments(filePath, lines))
+	if extract := extractorFor(filePath, opts); extract != nil {
+		lines = extract(lines)
+	}
+	directives.mask(lines)
+
+	var misspelledWords []MisspelledWord
+	for i, line := range lines {
+		lineNumber := i + 1
+		for _, tok := range tokenize(line) {
+			if isWordCorrect(tok.Text, dictionary) {
+				continue
+			}
+			parts := []token{tok}
+			if opts.SplitIdentifiers {
+				parts = splitIdentifier(tok)
+			}
+			for _, part := range parts {
+				word := part.Text
+				if !isWordCorrect(word, dictionary) && !directives.suppresses(i, word, tok.Text) {
+					// When a typo is found, generate suggestions.
+					suggestions := generateSuggestions(word, dictionary, opts.Suggestions)
+					typo := MisspelledWord{
+						Word:        word,
+						LineNumber:  lineNumber,
+						Column:      part.Column,
+						Suggestions: suggestions,
+					}
+					if _, forbidden := dictionary.Forbidden[normalizeWord(word)]; forbidden {
+						typo.Rule = RuleForbiddenWord
+					}
+					misspelledWords = append(misspelledWords, typo)
+				}
+			}
+		}
+	}
+
+	if opts.ReportUnusedDirectives && directives != nil {
+		misspelledWords = append(misspelledWords, directives.unused()...)
+		sort.SliceStable(misspelledWords, func(i, j int) bool {
+			a, b := misspelledWords[i], misspelledWords[j]
+			if a.LineNumber != b.LineNumber {
+				return a.LineNumber < b.LineNumber
+			}
+			return a.Column < b.Column
+		})
+	}
+	return misspelledWords
+}
+
+// readLines returns the lines of a file, without their line endings. On a read error,
+// the lines read so far are returned along with the error.
+func readLines(filePath string) ([]string, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return scanLines(file)
+}
+
+// scanLines returns the lines read from r. On error, the lines read so far are
+// returned with it.
+func scanLines(r io.Reader) ([]string, error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	return lines, scanner.Err()
+}
+
+func isWordCorrect(word string, dictionary *Dictionary) bool {
+	key := normalizeWord(word)
+	if _, forbidden := dictionary.Forbidden[key]; forbidden {
+		return false
+	}
+	if _, exists := dictionary.Words[key]; exists {
+		return true
+	}
+	return dictionary.compound != nil && dictionary.compound.accepts(key)
+}
+
+// isLikelyBinary reports whether a file looks binary: its first 512 bytes contain a
+// NUL byte.
+func isLikelyBinary(filePath string) (bool, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return false, err
+	}
+	defer file.Close()
+	buffer := make([]byte, 512)
+	n, err := file.Read(buffer)
+	if err != nil && err != io.EOF {
+		return false, err
+	}
+	return bytes.Contains(buffer[:n], []byte{0}), nil
+}
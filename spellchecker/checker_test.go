@@ -0,0 +1,356 @@
+package spellchecker
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// REWRITTEN: TestCheckFile is now a table-driven test for better coverage and readability.
+func TestCheckFile(t *testing.T) {
+	// A common dictionary for all test cases.
+	mockDictionary := &Dictionary{Words: map[string]struct{}{
+		"hello": {}, "world": {}, "they're": {}, "a": {}, "test": {},
+		"state-of-the-art": {}, "error": {}, "naïve": {}, "café": {},
+	}}
+
+	testCases := []struct {
+		name          string
+		fileContent   string
+		expectedTypos []MisspelledWord
+	}{
+		{
+			name:          "file with no typos",
+			fileContent:   "hello world",
+			expectedTypos: nil, // Expect nil or an empty slice
+		},
+		{
+			name:        "file with one typo and suggestions",
+			fileContent: "hello wrld",
+			expectedTypos: []MisspelledWord{
+				{Word: "wrld", LineNumber: 1, Column: 7, Suggestions: []string{"world"}},
+			},
+		},
+		{
+			name:          "file with correct contraction",
+			fileContent:   "they're a test",
+			expectedTypos: nil,
+		},
+		{
+			name:          "file with correct hyphenated word",
+			fileContent:   "a state-of-the-art test",
+			expectedTypos: nil,
+		},
+		{
+			name:        "file with misspelled hyphenated word",
+			fileContent: "a state-of-the-artt test",
+			expectedTypos: []MisspelledWord{
+				{Word: "state-of-the-artt", LineNumber: 1, Column: 3, Suggestions: []string{"state-of-the-art"}},
+			},
+		},
+		{
+			name:          "file with correct words containing diacritics",
+			fileContent:   "a naïve café, they’re a test",
+			expectedTypos: nil,
+		},
+		{
+			name:        "file with typo after multi-byte characters",
+			fileContent: "naïve café wrld",
+			expectedTypos: []MisspelledWord{
+				{Word: "wrld", LineNumber: 1, Column: 12, Suggestions: []string{"world"}},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Create a temporary file for the test case.
+			tempDir := t.TempDir()
+			filePath := filepath.Join(tempDir, "testfile.txt")
+			if err := os.WriteFile(filePath, []byte(tc.fileContent), 0644); err != nil {
+				t.Fatalf("Failed to write test file: %v", err)
+			}
+
+			gotTypos, _ := checkFile(filePath, mockDictionary, CheckOptions{})
+
+			// Normalize for comparison: treat a nil slice and an empty slice as the same.
+			if len(gotTypos) == 0 && len(tc.expectedTypos) == 0 {
+				return // They are effectively equal, so we pass.
+			}
+
+			if !reflect.DeepEqual(gotTypos, tc.expectedTypos) {
+				t.Errorf("checkFile() returned incorrect typos.\nGOT:\n%v\nWANT:\n%v", gotTypos, tc.expectedTypos)
+			}
+		})
+	}
+}
+
+// createFile writes content to the file relPath below root, creating its directories,
+// and returns the file's path.
+func createFile(t *testing.T, root, relPath, content string) string {
+	t.Helper()
+	fullPath := filepath.Join(root, filepath.FromSlash(relPath))
+	if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+		t.Fatalf("Failed to create directory for %s: %v", relPath, err)
+	}
+	if err := os.WriteFile(fullPath, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to create test file %s: %v", relPath, err)
+	}
+	return fullPath
+}
+
+func TestRunConcurrentChecker(t *testing.T) {
+	mockDictionary := &Dictionary{Words: map[string]struct{}{
+		"hello": {}, "world": {}, "this": {}, "is": {}, "a": {}, "test": {}, "some": {}, "text": {}, "package": {},
+	}}
+	tempDir := t.TempDir()
+
+	// Create a realistic test directory structure
+	createFile(t, tempDir, "file_with_typo.txt", "hello wrld")
+	createFile(t, tempDir, "file_no_typo.txt", "hello world")
+	createFile(t, tempDir, "report.log", "this is an errror")     // Should be excluded by pattern
+	createFile(t, tempDir, "a_binary_file.bin", "hello\x00world") // Should be excluded by pattern
+	createFile(t, tempDir, "image.dat", "wrld\x00anothr")         // Should be skipped as binary
+	createFile(t, tempDir, "subdir/another.txt", "anothr typo")
+	createFile(t, tempDir, "node_modules/package.json", "some tst here") // Should be skipped via directory exclusion
+
+	// Define exclusion patterns
+	opts := CheckOptions{Exclude: []string{"*.log", "*.bin", "node_modules"}}
+
+	// Run the concurrent checker on the temporary directory
+	results, summary, err := runConcurrentChecker(context.Background(), tempDir, mockDictionary, opts)
+	if err != nil {
+		t.Fatalf("runConcurrentChecker failed: %v", err)
+	}
+
+	// Three text files are checked; report.log and a_binary_file.bin are excluded and
+	// image.dat is skipped as binary.
+	// Files in the excluded node_modules directory are not counted.
+	wantSummary := ScanSummary{
+		FilesScanned:   3,
+		FilesSkipped:   3,
+		DictionarySize: len(mockDictionary.Words),
+		Files: []string{
+			filepath.Join(tempDir, "file_no_typo.txt"),
+			filepath.Join(tempDir, "file_with_typo.txt"),
+			filepath.Join(tempDir, "subdir/another.txt"),
+		},
+	}
+	if !reflect.DeepEqual(summary, wantSummary) {
+		t.Errorf("Expected summary %+v, but got %+v", wantSummary, summary)
+	}
+
+	// --- Assertions ---
+
+	// We expect typos to be found in exactly 2 files
+	if len(results) != 2 {
+		t.Errorf("Expected results for 2 files, but got %d for files: %v", len(results), results)
+	}
+
+	// Check that the correct files are present in the results map
+	expectedFilesWithTypos := []string{
+		filepath.Join(tempDir, "file_with_typo.txt"),
+		filepath.Join(tempDir, "subdir/another.txt"),
+	}
+	resultKeys := make([]string, 0, len(results))
+	for k := range results {
+		resultKeys = append(resultKeys, k)
+	}
+	sort.Strings(expectedFilesWithTypos)
+	sort.Strings(resultKeys)
+
+	if !reflect.DeepEqual(expectedFilesWithTypos, resultKeys) {
+		t.Errorf("Expected files with typos to be %v, but got %v", expectedFilesWithTypos, resultKeys)
+	}
+
+	// Verify the specific typos found in one of the files
+	filePath := filepath.Join(tempDir, "file_with_typo.txt")
+	if typos, ok := results[filePath]; ok {
+		if len(typos) != 1 {
+			t.Fatalf("Expected 1 typo in %s, but got %d", filePath, len(typos))
+		}
+		if typos[0].Word != "wrld" {
+			t.Errorf("Expected typo to be 'wrld', but got '%s'", typos[0].Word)
+		}
+	} else {
+		t.Errorf("Expected to find results for %s, but did not", filePath)
+	}
+}
+
+func TestCheckFileMarkdown(t *testing.T) {
+	mockDictionary := &Dictionary{Words: map[string]struct{}{
+		"hello": {}, "world": {}, "see": {}, "the": {}, "docs": {}, "run": {}, "and": {},
+	}}
+	content := "# Hello wrld\n\n```sh\nnpm instal\n```\n\nRun `go biuld` and see [the docs](https://exampel.com).\n"
+
+	filePath := filepath.Join(t.TempDir(), "README.md")
+	if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	want := []MisspelledWord{
+		{Word: "wrld", LineNumber: 1, Column: 9, Suggestions: []string{"world"}},
+	}
+	if got, _ := checkFile(filePath, mockDictionary, CheckOptions{}); !reflect.DeepEqual(got, want) {
+		t.Errorf("checkFile() returned incorrect typos.\nGOT:\n%v\nWANT:\n%v", got, want)
+	}
+}
+
+func TestCheckFileSourceCode(t *testing.T) {
+	mockDictionary := &Dictionary{Words: map[string]struct{}{
+		"returns": {}, "the": {}, "value": {}, "hello": {},
+	}}
+	content := "package mian\n\n// returns the valeu\nfunc f() string { return \"helo\" }\n"
+
+	filePath := filepath.Join(t.TempDir(), "f.go")
+	if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	t.Run("comments only", func(t *testing.T) {
+		want := []MisspelledWord{
+			{Word: "valeu", LineNumber: 3, Column: 16, Suggestions: []string{"value"}},
+		}
+		if got, _ := checkFile(filePath, mockDictionary, CheckOptions{}); !reflect.DeepEqual(got, want) {
+			t.Errorf("checkFile() returned incorrect typos.\nGOT:\n%v\nWANT:\n%v", got, want)
+		}
+	})
+
+	t.Run("comments and strings", func(t *testing.T) {
+		want := []MisspelledWord{
+			{Word: "valeu", LineNumber: 3, Column: 16, Suggestions: []string{"value"}},
+			{Word: "helo", LineNumber: 4, Column: 27, Suggestions: []string{"hello"}},
+		}
+		if got, _ := checkFile(filePath, mockDictionary, CheckOptions{CheckStrings: true}); !reflect.DeepEqual(got, want) {
+			t.Errorf("checkFile() returned incorrect typos.\nGOT:\n%v\nWANT:\n%v", got, want)
+		}
+	})
+}
+
+func TestCheckFileSplitIdentifiers(t *testing.T) {
+	mockDictionary := &Dictionary{Words: map[string]struct{}{
+		"call": {}, "parse": {}, "dictionary": {}, "http": {}, "server": {}, "report": {}, "iphone": {},
+	}}
+	content := "call parseDictionary, HTTPServer, iPhone and parseReprot"
+
+	filePath := filepath.Join(t.TempDir(), "notes.txt")
+	if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	t.Run("whole words", func(t *testing.T) {
+		got, _ := checkFile(filePath, mockDictionary, CheckOptions{})
+		var words []string
+		for _, m := range got {
+			words = append(words, m.Word)
+		}
+		want := []string{"parseDictionary", "HTTPServer", "and", "parseReprot"}
+		if !reflect.DeepEqual(words, want) {
+			t.Errorf("checkFile() flagged %v, want %v", words, want)
+		}
+	})
+
+	t.Run("split identifiers", func(t *testing.T) {
+		want := []MisspelledWord{
+			{Word: "and", LineNumber: 1, Column: 42, Suggestions: []string{}},
+			{Word: "Reprot", LineNumber: 1, Column: 51, Suggestions: []string{"report"}},
+		}
+		if got, _ := checkFile(filePath, mockDictionary, CheckOptions{SplitIdentifiers: true}); !reflect.DeepEqual(got, want) {
+			t.Errorf("checkFile() returned incorrect typos.\nGOT:\n%v\nWANT:\n%v", got, want)
+		}
+	})
+}
+
+func TestCheckFileForbidden(t *testing.T) {
+	mockDictionary := &Dictionary{
+		Words:     map[string]struct{}{"color": {}, "and": {}},
+		Forbidden: map[string]struct{}{"colour": {}},
+	}
+	filePath := filepath.Join(t.TempDir(), "notes.txt")
+	if err := os.WriteFile(filePath, []byte("Colour and colr"), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	want := []MisspelledWord{
+		{Word: "Colour", LineNumber: 1, Column: 1, Suggestions: []string{"color"}, Rule: RuleForbiddenWord},
+		{Word: "colr", LineNumber: 1, Column: 12, Suggestions: []string{"color"}},
+	}
+	if got, _ := checkFile(filePath, mockDictionary, CheckOptions{}); !reflect.DeepEqual(got, want) {
+		t.Errorf("checkFile() = %+v; want %+v", got, want)
+	}
+}
+
+func TestRunConcurrentCheckerErrors(t *testing.T) {
+	dictionary := &Dictionary{Words: map[string]struct{}{"hello": {}, "world": {}}}
+	dir := t.TempDir()
+	long := filepath.Join(dir, "long.txt")
+	// The typo before a line too long to read is still reported.
+	os.WriteFile(long, []byte("hello wrld\n"+strings.Repeat("a", bufio.MaxScanTokenSize)+"\nanothr\n"), 0644)
+	os.WriteFile(filepath.Join(dir, "ok.txt"), []byte("hello world"), 0644)
+
+	results, summary, err := runConcurrentChecker(context.Background(), dir, dictionary, CheckOptions{})
+	if err != nil {
+		t.Fatalf("runConcurrentChecker failed: %v", err)
+	}
+	if words := results[long]; len(words) != 1 || words[0].Word != "wrld" {
+		t.Errorf("results[long.txt] = %+v, want the typo before the long line", words)
+	}
+	if want := []string{filepath.Join(dir, "ok.txt")}; !reflect.DeepEqual(summary.Files, want) || summary.FilesScanned != 1 {
+		t.Errorf("summary.Files = %v (%d scanned), want %v", summary.Files, summary.FilesScanned, want)
+	}
+	if len(summary.Errors) != 1 || summary.Errors[0].Path != long || !errors.Is(summary.Errors[0], bufio.ErrTooLong) {
+		t.Errorf("summary.Errors = %v, want the long line of long.txt", summary.Errors)
+	}
+
+	// An entry below the root that cannot be read, such as a dangling symbolic link,
+	// does not stop the scan.
+	broken := filepath.Join(dir, "broken.txt")
+	if err := os.Symlink(filepath.Join(dir, "missing"), broken); err != nil {
+		t.Skipf("symbolic links are not supported: %v", err)
+	}
+	_, summary, err = runConcurrentChecker(context.Background(), dir, dictionary, CheckOptions{})
+	if err != nil {
+		t.Fatalf("runConcurrentChecker failed: %v", err)
+	}
+	if len(summary.Errors) != 2 || summary.Errors[0].Path != broken || !errors.Is(summary.Errors[0], fs.ErrNotExist) {
+		t.Fatalf("summary.Errors = %v, want the dangling link and the long line", summary.Errors)
+	}
+	if got, want := summary.Errors[0].Error(), "open "+broken+": no such file or directory"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+}
+
+func TestRunConcurrentCheckerCanceled(t *testing.T) {
+	dictionary := &Dictionary{Words: map[string]struct{}{"hello": {}}}
+	dir := t.TempDir()
+	for i := 0; i < 20; i++ {
+		os.WriteFile(filepath.Join(dir, fmt.Sprintf("%d.txt", i)), []byte("wrld"), 0644)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	results, summary, err := runConcurrentChecker(ctx, dir, dictionary, CheckOptions{})
+	if err != context.Canceled {
+		t.Errorf("runConcurrentChecker() error = %v, want %v", err, context.Canceled)
+	}
+	if len(results) != 0 || len(summary.Files) != 0 {
+		t.Errorf("runConcurrentChecker() checked %v after the context was canceled", summary.Files)
+	}
+}
+
+func TestScanSummaryMerge(t *testing.T) {
+	denied := FileError{Path: "secret.txt", Err: fs.ErrPermission}
+	summary := ScanSummary{FilesScanned: 2, FilesSkipped: 1, Files: []string{"a.txt", "docs/b.md"}, Errors: []FileError{denied}}
+	summary.Merge(ScanSummary{FilesScanned: 2, FilesSkipped: 2, Files: []string{"docs/b.md", "docs/c.md"}, Errors: []FileError{denied}})
+	want := ScanSummary{FilesScanned: 3, FilesSkipped: 3, Files: []string{"a.txt", "docs/b.md", "docs/c.md"}, Errors: []FileError{denied}}
+	if !reflect.DeepEqual(summary, want) {
+		t.Errorf("merge() = %+v, want %+v", summary, want)
+	}
+}
@@ -0,0 +1,322 @@
+package spellchecker
+
+import (
+	"strings"
+)
+
+// stringSyntax describes one kind of string literal in a programming language.
+type stringSyntax struct {
+	// Delim opens and closes the literal, e.g. `"` or `"""`.
+	Delim string
+	// Escapes reports whether a backslash escapes the next character.
+	Escapes bool
+	// Multiline reports whether the literal may span several lines.
+	Multiline bool
+	// Char marks character literals, which are never checked.
+	Char bool
+}
+
+// codeSyntax describes the comment and string syntax of a family of languages.
+type codeSyntax struct {
+	// LineComments start a comment that runs to the end of the line.
+	LineComments []string
+	// BlockComments are pairs of opening and closing comment delimiters.
+	BlockComments [][2]string
+	// Strings lists the string literal forms, longest delimiter first.
+	Strings []stringSyntax
+	// HashNeedsSpace restricts '#' comments to the start of a line or after whitespace,
+	// as in shells and YAML, where '#' is also used inside words ($#, URL fragments).
+	HashNeedsSpace bool
+	// QuotedScalars only opens a string at the start of a YAML scalar, so that the
+	// apostrophe of a plain scalar such as "it's fine" is not a quote.
+	QuotedScalars bool
+	// Lifetimes tells Rust lifetimes and loop labels such as 'a and 'static apart from
+	// character literals, which are closed right after one character or escape.
+	Lifetimes bool
+}
+
+// Not modeled: C++ raw strings R"(...)", Rust raw strings r#"..."#, C# verbatim strings
+// @"..." and Swift extended delimiters #"..."#. Their quotes are read as those of an
+// ordinary string, which is only wrong when the literal contains a quote or spans lines.
+var (
+	cFamilySyntax = &codeSyntax{
+		LineComments:  []string{"//"},
+		BlockComments: [][2]string{{"/*", "*/"}},
+		Strings: []stringSyntax{
+			{Delim: `"`, Escapes: true},
+			{Delim: `'`, Escapes: true, Char: true},
+		},
+	}
+	// textBlockSyntax covers Java text blocks and Swift multi-line strings, """ literals
+	// that support escapes.
+	textBlockSyntax = &codeSyntax{
+		LineComments:  []string{"//"},
+		BlockComments: [][2]string{{"/*", "*/"}},
+		Strings: []stringSyntax{
+			{Delim: `"""`, Escapes: true, Multiline: true},
+			{Delim: `"`, Escapes: true},
+			{Delim: `'`, Escapes: true, Char: true},
+		},
+	}
+	// rawTextSyntax covers Kotlin and Scala raw strings and C# raw string literals,
+	// """ literals without escapes.
+	rawTextSyntax = &codeSyntax{
+		LineComments:  []string{"//"},
+		BlockComments: [][2]string{{"/*", "*/"}},
+		Strings: []stringSyntax{
+			{Delim: `"""`, Multiline: true},
+			{Delim: `"`, Escapes: true},
+			{Delim: `'`, Escapes: true, Char: true},
+		},
+	}
+	rustSyntax = &codeSyntax{
+		LineComments:  []string{"//"},
+		BlockComments: [][2]string{{"/*", "*/"}},
+		Strings: []stringSyntax{
+			{Delim: `"`, Escapes: true, Multiline: true},
+			{Delim: `'`, Escapes: true, Char: true},
+		},
+		Lifetimes: true,
+	}
+	goSyntax = &codeSyntax{
+		LineComments:  []string{"//"},
+		BlockComments: [][2]string{{"/*", "*/"}},
+		Strings: []stringSyntax{
+			{Delim: `"`, Escapes: true},
+			{Delim: "`", Multiline: true},
+			{Delim: `'`, Escapes: true, Char: true},
+		},
+	}
+	javaScriptSyntax = &codeSyntax{
+		LineComments:  []string{"//"},
+		BlockComments: [][2]string{{"/*", "*/"}},
+		Strings: []stringSyntax{
+			{Delim: `"`, Escapes: true},
+			{Delim: `'`, Escapes: true},
+			{Delim: "`", Escapes: true, Multiline: true},
+		},
+	}
+	pythonSyntax = &codeSyntax{
+		LineComments: []string{"#"},
+		Strings: []stringSyntax{
+			{Delim: `"""`, Escapes: true, Multiline: true},
+			{Delim: `'''`, Escapes: true, Multiline: true},
+			{Delim: `"`, Escapes: true},
+			{Delim: `'`, Escapes: true},
+		},
+	}
+	shellSyntax = &codeSyntax{
+		LineComments: []string{"#"},
+		Strings: []stringSyntax{
+			{Delim: `"`, Escapes: true, Multiline: true},
+			{Delim: `'`, Multiline: true},
+		},
+		HashNeedsSpace: true,
+	}
+	yamlSyntax = &codeSyntax{
+		LineComments: []string{"#"},
+		Strings: []stringSyntax{
+			{Delim: `"`, Escapes: true, Multiline: true},
+			{Delim: `'`, Multiline: true},
+		},
+		HashNeedsSpace: true,
+		QuotedScalars:  true,
+	}
+	// ignoreFileSyntax covers .gitignore-style files, where only comments are prose.
+	ignoreFileSyntax = &codeSyntax{
+		LineComments:   []string{"#"},
+		HashNeedsSpace: true,
+	}
+)
+
+// codeSyntaxes maps a lowercase file extension to the syntax of its language.
+var codeSyntaxes = map[string]*codeSyntax{
+	".go": goSyntax,
+
+	".c": cFamilySyntax, ".h": cFamilySyntax, ".cc": cFamilySyntax, ".cpp": cFamilySyntax,
+	".cxx": cFamilySyntax, ".hpp": cFamilySyntax,
+
+	".java": textBlockSyntax, ".swift": textBlockSyntax,
+	".kt": rawTextSyntax, ".scala": rawTextSyntax, ".cs": rawTextSyntax,
+
+	".rs": rustSyntax,
+
+	".js": javaScriptSyntax, ".jsx": javaScriptSyntax, ".mjs": javaScriptSyntax, ".cjs": javaScriptSyntax,
+	".ts": javaScriptSyntax, ".tsx": javaScriptSyntax, ".mts": javaScriptSyntax, ".cts": javaScriptSyntax,
+
+	".py": pythonSyntax, ".pyi": pythonSyntax,
+
+	".sh": shellSyntax, ".bash": shellSyntax, ".zsh": shellSyntax,
+
+	".yml": yamlSyntax, ".yaml": yamlSyntax,
+}
+
+// codeSyntaxByName maps well-known file names without an extension to their syntax.
+var codeSyntaxByName = map[string]*codeSyntax{
+	"Makefile":   shellSyntax,
+	"Dockerfile": shellSyntax,
+
+	".gitignore":        ignoreFileSyntax,
+	".spellcheckignore": ignoreFileSyntax,
+}
+
+// extractor returns a textExtractor that keeps comments and, if checkStrings is set,
+// the contents of string literals. Code, delimiters and escape sequences are blanked.
+func (s *codeSyntax) extractor(checkStrings bool) textExtractor {
+	return func(lines []string) []string {
+		return extractCode(lines, s, checkStrings)
+	}
+}
+
+// codeState records which construct the lexer is inside of; it carries over between lines.
+type codeState struct {
+	// block is the index+1 of the open block comment in BlockComments, or 0.
+	block int
+	// str is the index+1 of the open string literal in Strings, or 0.
+	str int
+}
+
+// extractCode blanks everything in lines except comments and, optionally, string literals.
+func extractCode(lines []string, syntax *codeSyntax, checkStrings bool) []string {
+	out := make([]string, len(lines))
+	var state codeState
+
+	for n, line := range lines {
+		runes := []rune(line)
+		keep := make([]bool, len(runes))
+		i := 0
+		if n == 0 && strings.HasPrefix(line, "#!") {
+			// A shebang line is an interpreter path, not a comment.
+			i = len(runes)
+		}
+
+	scan:
+		for i < len(runes) {
+			switch {
+			case state.block > 0:
+				end := syntax.BlockComments[state.block-1][1]
+				if hasPrefixAt(runes, i, end) {
+					state.block = 0
+					i += len([]rune(end))
+					continue
+				}
+				keep[i] = true
+				i++
+
+			case state.str > 0:
+				str := syntax.Strings[state.str-1]
+				if str.Escapes && runes[i] == '\\' {
+					i += 2
+					continue
+				}
+				if hasPrefixAt(runes, i, str.Delim) {
+					state.str = 0
+					i += len([]rune(str.Delim))
+					continue
+				}
+				keep[i] = checkStrings && !str.Char
+				i++
+
+			default:
+				for _, marker := range syntax.LineComments {
+					if hasPrefixAt(runes, i, marker) && (!syntax.HashNeedsSpace || marker != "#" || i == 0 || isSpace(runes[i-1])) {
+						for j := i + len([]rune(marker)); j < len(runes); j++ {
+							keep[j] = true
+						}
+						break scan
+					}
+				}
+				if idx := matchBlockComment(runes, i, syntax); idx > 0 {
+					state.block = idx
+					i += len([]rune(syntax.BlockComments[idx-1][0]))
+					continue
+				}
+				if idx := matchString(runes, i, syntax); idx > 0 && (!syntax.QuotedScalars || scalarStart(runes, i)) &&
+					(!syntax.Lifetimes || !isLifetime(runes, i)) {
+					state.str = idx
+					i += len([]rune(syntax.Strings[idx-1].Delim))
+					continue
+				}
+				i++
+			}
+		}
+
+		if state.str > 0 && !syntax.Strings[state.str-1].Multiline {
+			// An unterminated single-line string ends with the line.
+			state.str = 0
+		}
+
+		for j := range runes {
+			if !keep[j] {
+				runes[j] = ' '
+			}
+		}
+		out[n] = string(runes)
+	}
+	return out
+}
+
+// matchBlockComment returns the index+1 of the block comment opening at runes[i], or 0.
+func matchBlockComment(runes []rune, i int, syntax *codeSyntax) int {
+	for idx, pair := range syntax.BlockComments {
+		if hasPrefixAt(runes, i, pair[0]) {
+			return idx + 1
+		}
+	}
+	return 0
+}
+
+// matchString returns the index+1 of the string literal opening at runes[i], or 0.
+func matchString(runes []rune, i int, syntax *codeSyntax) int {
+	for idx, str := range syntax.Strings {
+		if hasPrefixAt(runes, i, str.Delim) {
+			return idx + 1
+		}
+	}
+	return 0
+}
+
+// scalarStart reports whether a YAML scalar may start at runes[i]: at the start of the
+// line, after "[", "{" or ",", or after ": " or "- ".
+func scalarStart(runes []rune, i int) bool {
+	j := i - 1
+	for j >= 0 && isSpace(runes[j]) {
+		j--
+	}
+	if j < 0 {
+		return true
+	}
+	switch runes[j] {
+	case '[', '{', ',':
+		return true
+	case ':', '-':
+		return j < i-1
+	}
+	return false
+}
+
+// isLifetime reports whether the quote at runes[i] starts a Rust lifetime or loop
+// label rather than a character literal: it is not followed by an escape, nor closed
+// right after one character.
+func isLifetime(runes []rune, i int) bool {
+	if runes[i] != '\'' || i+1 >= len(runes) || runes[i+1] == '\\' {
+		return false
+	}
+	return i+2 >= len(runes) || runes[i+2] != '\''
+}
+
+// hasPrefixAt reports whether runes[i:] starts with prefix.
+func hasPrefixAt(runes []rune, i int, prefix string) bool {
+	for _, r := range prefix {
+		if i >= len(runes) || runes[i] != r {
+			return false
+		}
+		i++
+	}
+	return true
+}
+
+// isSpace reports whether r is a space or tab.
+func isSpace(r rune) bool {
+	return r == ' ' || r == '\t'
+}
@@ -0,0 +1,130 @@
+package spellchecker
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestExtractCode(t *testing.T) {
+	testCases := []struct {
+		name         string
+		syntax       *codeSyntax
+		checkStrings bool
+		lines        []string
+		want         []string
+	}{
+		{
+			name:   "go line and block comments",
+			syntax: goSyntax,
+			lines:  []string{"x := 1 // set the valeu", "/* a blok", "comment */ y := 2"},
+			want:   []string{"          set the valeu", "   a blok", "comment          "},
+		},
+		{
+			name:   "strings are skipped by default",
+			syntax: goSyntax,
+			lines:  []string{`fmt.Println("helo") // greet`},
+			want:   []string{`                       greet`},
+		},
+		{
+			name:         "strings are kept on request, without escapes",
+			syntax:       goSyntax,
+			checkStrings: true,
+			lines:        []string{`s := "helo\nwrld" + 'x'`},
+			want:         []string{`      helo  wrld       `},
+		},
+		{
+			name:         "go raw strings span lines",
+			syntax:       goSyntax,
+			checkStrings: true,
+			lines:        []string{"q := `frist", "secnd` // end"},
+			want:         []string{"      frist", "secnd     end"},
+		},
+		{
+			name:   "comment markers inside strings are ignored",
+			syntax: javaScriptSyntax,
+			lines:  []string{`const url = "http://example.com"; // real`},
+			want:   []string{`                                     real`},
+		},
+		{
+			name:         "python docstrings and hash comments",
+			syntax:       pythonSyntax,
+			checkStrings: true,
+			lines:        []string{`"""Modle docs`, `more"""  # note`},
+			want:         []string{`   Modle docs`, `more       note`},
+		},
+		{
+			name:   "rust lifetimes are not character literals",
+			syntax: rustSyntax,
+			lines:  []string{"fn f<'a>(s: &'a str) -> char { 'x' } // retrn it", "let q = '\\''; 'outer: loop {} // lbel"},
+			want:   []string{"                                        retrn it", "                                 lbel"},
+		},
+		{
+			name:         "triple-quoted strings span lines",
+			syntax:       rawTextSyntax,
+			checkStrings: true,
+			lines:        []string{`val s = """frist "quoted"`, `secnd\""" // end`},
+			want:         []string{`           frist "quoted"`, `secnd\       end`},
+		},
+		{
+			name:   "shell hash needs a preceding space",
+			syntax: shellSyntax,
+			lines:  []string{"#!/bin/bash", "echo $# ${#arr} # count args"},
+			want:   []string{"           ", "                  count args"},
+		},
+		{
+			name:   "yaml comments",
+			syntax: yamlSyntax,
+			lines:  []string{"# Top-level setings", "url: http://x.org/#frag  # trailng"},
+			want:   []string{"  Top-level setings", "                           trailng"},
+		},
+		{
+			name:         "yaml apostrophes in plain scalars are not quotes",
+			syntax:       yamlSyntax,
+			checkStrings: true,
+			lines:        []string{"description: it's fine", "# a commnet here", "items: ['frist', \"secnd\"]"},
+			want:         []string{"                      ", "  a commnet here", "         frist    secnd  "},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := extractCode(tc.lines, tc.syntax, tc.checkStrings)
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("extractCode() mismatch.\nGOT:\n%q\nWANT:\n%q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestExtractorFor(t *testing.T) {
+	testCases := []struct {
+		path     string
+		wantCode bool
+		wantNil  bool
+	}{
+		{"main.go", true, false},
+		{"src/App.TSX", true, false},
+		{"scripts/build.sh", true, false},
+		{"Makefile", true, false},
+		{"docs/.gitignore", true, false},
+		{"docs/index.md", false, false},
+		{"notes.txt", false, true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.path, func(t *testing.T) {
+			extract := extractorFor(tc.path, CheckOptions{})
+			if (extract == nil) != tc.wantNil {
+				t.Fatalf("extractorFor(%q) = %v, want nil: %v", tc.path, extract, tc.wantNil)
+			}
+			if extract == nil {
+				return
+			}
+			// Code extractors blank code outside comments; the Markdown extractor keeps it.
+			got := strings.TrimSpace(extract([]string{"identifier"})[0])
+			if (got == "") != tc.wantCode {
+				t.Errorf("extractorFor(%q) kept %q, want code extractor: %v", tc.path, got, tc.wantCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package spellchecker
+
+import (
+	"bufio"
+	"bytes"
+	_ "embed"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+//go:embed dictionary.csv
+var dictionaryData []byte
+
+// Dictionary is the set of words accepted by the checker. All keys are in the form
+// returned by normalizeWord.
+type Dictionary struct {
+	// Words holds every accepted word.
+	Words map[string]struct{}
+	// NoSuggest holds accepted words that are never offered as suggestions.
+	NoSuggest map[string]struct{}
+	// Forbidden holds words that are always reported, even when compounding would accept them.
+	Forbidden map[string]struct{}
+	// Frequency ranks equally close suggestions; words without an entry count as 0.
+	Frequency map[string]int
+
+	// compound, when set, also accepts words made by joining dictionary words; it is
+	// set from the compounding rules of a Hunspell .aff file.
+	compound *compoundRules
+	// index answers approximate-match queries for suggestions; see BuildIndex.
+	index *wordTrie
+	// phonetic maps Double Metaphone keys to words; see BuildPhoneticIndex. Without
+	// it, phoneticOnce builds it on first use.
+	phonetic     phoneticIndex
+	phoneticOnce sync.Once
+}
+
+// newDictionary returns an empty dictionary.
+func newDictionary() *Dictionary {
+	return &Dictionary{
+		Words:     make(map[string]struct{}),
+		NoSuggest: make(map[string]struct{}),
+		Forbidden: make(map[string]struct{}),
+		Frequency: make(map[string]int),
+	}
+}
+
+// LoadDictionary loads the embedded dictionary, or the one at customPath if set. A path
+// ending in .dic or .aff loads a Hunspell dictionary from the .dic/.aff pair with that
+// base name; any other path is read as a CSV dictionary.
+func LoadDictionary(customPath string) (*Dictionary, error) {
+	var reader io.Reader
+	if ext := strings.ToLower(filepath.Ext(customPath)); ext == ".dic" || ext == ".aff" {
+		base := strings.TrimSuffix(customPath, filepath.Ext(customPath))
+		return loadHunspellDictionary(base+".aff", base+".dic")
+	}
+	if customPath != "" {
+		file, err := os.Open(customPath)
+		if err != nil {
+			return nil, fmt.Errorf("could not open custom dictionary: %w", err)
+		}
+		defer file.Close()
+		reader = file
+	} else {
+		reader = bytes.NewReader(dictionaryData)
+	}
+	return parseDictionary(reader)
+}
+
+// parseDictionary reads a CSV dictionary whose first column is a word. If the header
+// has a "frequency", "freq" or "count" column, it gives each word's frequency;
+// otherwise a word's frequency is the number of rows it has (one per sense in
+// dictionaries such as Webster's), so common words with many senses rank first.
+func parseDictionary(reader io.Reader) (*Dictionary, error) {
+	dictionary := newDictionary()
+	csvReader := csv.NewReader(reader)
+	header, err := csvReader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("could not read dictionary header: %w", err)
+	}
+	freqColumn := -1
+	for i, name := range header {
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case "frequency", "freq", "count":
+			freqColumn = i
+		}
+	}
+	for {
+		record, err := csvReader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error reading dictionary record: %w", err)
+		}
+		if len(record) > 0 {
+			key := normalizeWord(record[0])
+			dictionary.Words[key] = struct{}{}
+			if freqColumn < 0 {
+				dictionary.Frequency[key]++
+			} else if freqColumn < len(record) {
+				if n, err := strconv.Atoi(strings.TrimSpace(record[freqColumn])); err == nil {
+					dictionary.Frequency[key] = max(dictionary.Frequency[key], n)
+				}
+			}
+		}
+	}
+	return dictionary, nil
+}
+
+// LoadPersonalDictionary adds the words of a personal dictionary, one per line, to
+// dictionary. Blank lines and lines starting with # are skipped. It returns the number
+// of words added.
+func LoadPersonalDictionary(path string, dictionary *Dictionary) (int, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, fmt.Errorf("could not open personal dictionary: %w", err)
+	}
+	defer file.Close()
+
+	count := 0
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		word := strings.TrimSpace(scanner.Text())
+		// Ignore empty lines or comments
+		if word != "" && !strings.HasPrefix(word, "#") {
+			key := normalizeWord(word)
+			dictionary.Words[key] = struct{}{}
+			delete(dictionary.Forbidden, key)
+			count++
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return 0, fmt.Errorf("error reading personal dictionary: %w", err)
+	}
+
+	return count, nil
+}
+
+// normalizeWord returns the form of a word used as a dictionary key: NFC-composed,
+// lowercased, and with typographic apostrophes replaced by ASCII ones.
+func normalizeWord(word string) string {
+	return strings.ReplaceAll(strings.ToLower(norm.NFC.String(word)), "’", "'")
+}
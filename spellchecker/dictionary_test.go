@@ -1,4 +1,4 @@
-package main
+package spellchecker
 
 import (
 	"os"
@@ -20,18 +20,18 @@ Golang,"a programming language"`
 			t.Fatalf("Expected no error, but got %v", err)
 		}
 
-		if len(dict) != 3 {
-			t.Errorf("Expected dictionary length to be 3, but got %d", len(dict))
+		if len(dict.Words) != 3 {
+			t.Errorf("Expected dictionary length to be 3, but got %d", len(dict.Words))
 		}
 
 		// Check for a word (should be stored in lowercase)
-		if _, ok := dict["hello"]; !ok {
+		if _, ok := dict.Words["hello"]; !ok {
 			t.Error("Expected 'hello' to be in the dictionary")
 		}
-		if _, ok := dict["golang"]; !ok {
+		if _, ok := dict.Words["golang"]; !ok {
 			t.Error("Expected 'golang' to be in the dictionary (case-insensitive)")
 		}
-		if _, ok := dict["goodbye"]; ok {
+		if _, ok := dict.Words["goodbye"]; ok {
 			t.Error("Expected 'goodbye' to not be in the dictionary")
 		}
 	})
@@ -47,10 +47,10 @@ Golang,"a programming language"`
 
 func TestLoadPersonalDictionary(t *testing.T) {
 	// 1. Create a pre-existing dictionary.
-	existingDict := map[string]struct{}{
+	existingDict := &Dictionary{Words: map[string]struct{}{
 		"hello": {},
 		"world": {},
-	}
+	}}
 
 	// 2. Create a temporary personal dictionary file.
 	content := `
@@ -71,9 +71,9 @@ func TestLoadPersonalDictionary(t *testing.T) {
 	tmpFile.Close()
 
 	// 3. Run the function to load and merge the words.
-	count, err := loadPersonalDictionary(tmpFile.Name(), existingDict)
+	count, err := LoadPersonalDictionary(tmpFile.Name(), existingDict)
 	if err != nil {
-		t.Fatalf("loadPersonalDictionary failed: %v", err)
+		t.Fatalf("LoadPersonalDictionary failed: %v", err)
 	}
 
 	// 4. Assertions.
@@ -82,13 +82,53 @@ func TestLoadPersonalDictionary(t *testing.T) {
 	}
 
 	expectedWords := []string{"hello", "world", "qopper", "fluxcapacitor", "bigcorp-api"}
-	if len(existingDict) != len(expectedWords) {
-		t.Errorf("Expected final dictionary size to be %d, but got %d", len(expectedWords), len(existingDict))
+	if len(existingDict.Words) != len(expectedWords) {
+		t.Errorf("Expected final dictionary size to be %d, but got %d", len(expectedWords), len(existingDict.Words))
 	}
 
 	for _, word := range expectedWords {
-		if _, ok := existingDict[word]; !ok {
+		if _, ok := existingDict.Words[word]; !ok {
 			t.Errorf("Expected dictionary to contain '%s', but it did not", word)
 		}
 	}
 }
+
+func TestNormalizeWord(t *testing.T) {
+	testCases := []struct {
+		word, want string
+	}{
+		{"Hello", "hello"},
+		{"CAFE\u0301", "caf\u00e9"},
+		{"They’re", "they're"},
+		{"Zürich", "zürich"},
+	}
+	for _, tc := range testCases {
+		if got := normalizeWord(tc.word); got != tc.want {
+			t.Errorf("normalizeWord(%q) = %q; want %q", tc.word, got, tc.want)
+		}
+	}
+}
+
+func TestParseDictionaryFrequency(t *testing.T) {
+	t.Run("counts rows per word", func(t *testing.T) {
+		csvData := "word,pos,def\nset,v.,\"to put\"\nset,n.,\"a group\"\nSet,n.,\"a collection\"\nsetter,n.,\"one who sets\"\n"
+		dict, err := parseDictionary(strings.NewReader(csvData))
+		if err != nil {
+			t.Fatalf("Expected no error, but got %v", err)
+		}
+		if dict.Frequency["set"] != 3 || dict.Frequency["setter"] != 1 {
+			t.Errorf("Expected frequencies set=3 setter=1, but got %v", dict.Frequency)
+		}
+	})
+
+	t.Run("reads a frequency column", func(t *testing.T) {
+		csvData := "word,frequency\nthe,5000\nthee,12\n"
+		dict, err := parseDictionary(strings.NewReader(csvData))
+		if err != nil {
+			t.Fatalf("Expected no error, but got %v", err)
+		}
+		if dict.Frequency["the"] != 5000 || dict.Frequency["thee"] != 12 {
+			t.Errorf("Expected frequencies the=5000 thee=12, but got %v", dict.Frequency)
+		}
+	})
+}
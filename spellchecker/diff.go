@@ -0,0 +1,269 @@
+package spellchecker
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// hunkHeader matches the header of a unified diff hunk, e.g. "@@ -12,3 +12,4 @@".
+// A missing count means 1.
+var hunkHeader = regexp.MustCompile(`^@@ -(\d+)(?:,(\d+))? \+(\d+)(?:,(\d+))? @@`)
+
+// lineRange is an inclusive range of 1-based line numbers.
+type lineRange struct {
+	start, end int
+}
+
+// ChangeSet records the lines added or modified by a diff, per file. When a check is
+// restricted to a change set, only changed files are scanned and only the typos on
+// their changed lines are reported.
+type ChangeSet struct {
+	// base is the absolute directory the paths of files are relative to.
+	base string
+	// files maps a slash-separated path to its changed lines, sorted and merged.
+	files map[string][]lineRange
+	// dirs holds every directory containing a changed file, relative to base.
+	dirs map[string]bool
+}
+
+func newChangeSet(base string, files map[string][]lineRange) (*ChangeSet, error) {
+	abs, err := filepath.Abs(base)
+	if err != nil {
+		return nil, err
+	}
+	c := &ChangeSet{base: abs, files: make(map[string][]lineRange), dirs: make(map[string]bool)}
+	for file, ranges := range files {
+		if len(ranges) == 0 {
+			// Only deleted lines: nothing left to check.
+			continue
+		}
+		c.files[file] = mergeRanges(ranges)
+		for dir := filepath.ToSlash(filepath.Dir(file)); dir != "."; dir = filepath.ToSlash(filepath.Dir(dir)) {
+			c.dirs[dir] = true
+		}
+	}
+	return c, nil
+}
+
+// mergeRanges sorts ranges and merges those that overlap or touch.
+func mergeRanges(ranges []lineRange) []lineRange {
+	sort.Slice(ranges, func(i, j int) bool { return ranges[i].start < ranges[j].start })
+	merged := ranges[:1]
+	for _, r := range ranges[1:] {
+		last := &merged[len(merged)-1]
+		if r.start <= last.end+1 {
+			last.end = max(last.end, r.end)
+			continue
+		}
+		merged = append(merged, r)
+	}
+	return merged
+}
+
+// relative returns path relative to the change set's base, slash-separated.
+func (c *ChangeSet) relative(path string) string {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return ""
+	}
+	rel, err := filepath.Rel(c.base, abs)
+	if err != nil {
+		return ""
+	}
+	return filepath.ToSlash(rel)
+}
+
+// Len returns the number of files with changed lines.
+func (c *ChangeSet) Len() int {
+	return len(c.files)
+}
+
+// hasFile reports whether the file path has changed lines.
+func (c *ChangeSet) hasFile(path string) bool {
+	_, ok := c.files[c.relative(path)]
+	return ok
+}
+
+// hasDir reports whether the directory path contains a file with changed lines.
+func (c *ChangeSet) hasDir(path string) bool {
+	rel := c.relative(path)
+	return rel == "." || c.dirs[rel]
+}
+
+// filter returns the typos of the file path that are on a changed line.
+func (c *ChangeSet) filter(path string, typos []MisspelledWord) []MisspelledWord {
+	ranges := c.files[c.relative(path)]
+	var kept []MisspelledWord
+	for _, typo := range typos {
+		i := sort.Search(len(ranges), func(i int) bool { return ranges[i].end >= typo.LineNumber })
+		if i < len(ranges) && ranges[i].start <= typo.LineNumber {
+			kept = append(kept, typo)
+		}
+	}
+	return kept
+}
+
+// parseUnifiedDiff returns the added lines of each file of a unified diff, as produced
+// by "git diff" or "diff -u", keyed by the path of the new file. A "b/" prefix is
+// removed from paths; deleted files and binary changes have no entry.
+func parseUnifiedDiff(r io.Reader) (map[string][]lineRange, error) {
+	files := make(map[string][]lineRange)
+	var file string
+	// line is the number of the next line of the new file; oldLeft and newLeft count
+	// the lines remaining in the current hunk.
+	var line, oldLeft, newLeft int
+
+	reader := bufio.NewReader(r)
+	for {
+		text, err := reader.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+		if text == "" && err == io.EOF {
+			break
+		}
+		text = strings.TrimRight(text, "\r\n")
+
+		if oldLeft > 0 || newLeft > 0 {
+			switch {
+			case strings.HasPrefix(text, "+"):
+				if file != "" {
+					files[file] = append(files[file], lineRange{line, line})
+				}
+				line++
+				newLeft--
+			case strings.HasPrefix(text, "-"):
+				oldLeft--
+			case strings.HasPrefix(text, " "), text == "":
+				// Some tools strip the trailing space of empty context lines.
+				line++
+				oldLeft--
+				newLeft--
+			}
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(text, "+++ "):
+			file = diffPath(text[len("+++ "):])
+		case strings.HasPrefix(text, "@@ "):
+			m := hunkHeader.FindStringSubmatch(text)
+			if m == nil {
+				return nil, fmt.Errorf("malformed hunk header %q", text)
+			}
+			oldLeft = hunkCount(m[2])
+			line, _ = strconv.Atoi(m[3])
+			newLeft = hunkCount(m[4])
+		}
+		if err == io.EOF {
+			break
+		}
+	}
+	return files, nil
+}
+
+func hunkCount(s string) int {
+	if s == "" {
+		return 1
+	}
+	n, _ := strconv.Atoi(s)
+	return n
+}
+
+// diffPath returns the file path of a "+++" header, or "" for /dev/null.
+func diffPath(s string) string {
+	if strings.HasPrefix(s, `"`) {
+		// Git quotes paths with unusual characters, with C-style escapes.
+		if end := strings.LastIndex(s, `"`); end > 0 {
+			if unquoted, err := strconv.Unquote(s[:end+1]); err == nil {
+				s = unquoted
+			}
+		}
+	} else if tab := strings.IndexByte(s, '\t'); tab >= 0 {
+		// diff -u appends the modification time after a tab.
+		s = s[:tab]
+	}
+	if s == "/dev/null" {
+		return ""
+	}
+	return strings.TrimPrefix(s, "b/")
+}
+
+// ReadDiffFile returns the change set of a unified diff read from path, or from
+// standard input if path is "-". Paths in the diff are relative to the current
+// directory.
+func ReadDiffFile(path string) (*ChangeSet, error) {
+	var r io.Reader = os.Stdin
+	if path != "-" {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		r = file
+	}
+	files, err := parseUnifiedDiff(r)
+	if err != nil {
+		return nil, fmt.Errorf("reading diff %s: %w", path, err)
+	}
+	return newChangeSet(".", files)
+}
+
+// GitChanges returns the changes made to root, a file or a directory inside a Git
+// repository, since it diverged from rev: the working tree, including uncommitted
+// and untracked files, is compared with the merge base of rev and HEAD.
+func GitChanges(root, rev string) (*ChangeSet, error) {
+	dir := root
+	if info, err := os.Stat(root); err == nil && !info.IsDir() {
+		dir = filepath.Dir(root)
+	}
+	base, err := runGit(dir, "merge-base", rev, "HEAD")
+	if err != nil {
+		return nil, err
+	}
+	// With --relative, paths are relative to dir and changes outside it are left out.
+	// The prefixes are pinned, as settings such as diff.mnemonicPrefix change them.
+	diff, err := runGit(dir, "-c", "core.quotePath=false", "diff", "--relative", "--unified=0",
+		"--no-color", "--no-ext-diff", "--src-prefix=a/", "--dst-prefix=b/",
+		strings.TrimSpace(string(base)), "--", ".")
+	if err != nil {
+		return nil, err
+	}
+	files, err := parseUnifiedDiff(bytes.NewReader(diff))
+	if err != nil {
+		return nil, fmt.Errorf("reading git diff: %w", err)
+	}
+
+	untracked, err := runGit(dir, "ls-files", "--others", "--exclude-standard", "-z")
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range strings.Split(string(untracked), "\x00") {
+		if file != "" {
+			files[file] = []lineRange{{1, math.MaxInt}}
+		}
+	}
+	return newChangeSet(dir, files)
+}
+
+// runGit runs git in dir and returns its standard output.
+func runGit(dir string, args ...string) ([]byte, error) {
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git %s: %v: %s", strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+	return out, nil
+}
@@ -0,0 +1,193 @@
+package spellchecker
+
+import (
+	"context"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseUnifiedDiff(t *testing.T) {
+	diff := `diff --git a/notes.txt b/notes.txt
+index 1111111..2222222 100644
+--- a/notes.txt
++++ b/notes.txt
+@@ -1,4 +1,5 @@
+ first line
+-old second line
++new second line
++added third line
+ fourth line
+
+@@ -10 +11 @@ func main() {
+--- a removed line that looks like a header
++++ an added line that looks like a header
+diff --git a/docs/new.md b/docs/new.md
+new file mode 100644
+--- /dev/null
++++ b/docs/new.md
+@@ -0,0 +1,2 @@
++hello
++world
+\ No newline at end of file
+diff --git a/gone.txt b/gone.txt
+deleted file mode 100644
+--- a/gone.txt
++++ /dev/null
+@@ -1 +0,0 @@
+-bye
+diff --git "a/caf\303\251.txt" "b/caf\303\251.txt"
+--- "a/caf\303\251.txt"
++++ "b/caf\303\251.txt"
+@@ -3,0 +4 @@
++menu
+Binary files a/logo.png and b/logo.png differ
+`
+	got, err := parseUnifiedDiff(strings.NewReader(diff))
+	if err != nil {
+		t.Fatalf("parseUnifiedDiff() error = %v", err)
+	}
+	want := map[string][]lineRange{
+		"notes.txt":   {{2, 2}, {3, 3}, {11, 11}},
+		"docs/new.md": {{1, 1}, {2, 2}},
+		"café.txt":    {{4, 4}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseUnifiedDiff() = %v, want %v", got, want)
+	}
+
+	if _, err := parseUnifiedDiff(strings.NewReader("+++ b/a.txt\n@@ -1 +x @@\n")); err == nil {
+		t.Error("parseUnifiedDiff() error = nil, want an error for a malformed hunk header")
+	}
+}
+
+func TestDiffPath(t *testing.T) {
+	testCases := map[string]string{
+		"b/src/main.go":                     "src/main.go",
+		"/dev/null":                         "",
+		"notes.txt\t2024-01-02 10:00:00":    "notes.txt",
+		`"b/with \"quotes\".txt"`:           `with "quotes".txt`,
+		`"b/na\303\257ve.txt"`:              "naïve.txt",
+		"b/dir with spaces/file name.txt":   "dir with spaces/file name.txt",
+		"plain/path/without/git/prefix.txt": "plain/path/without/git/prefix.txt",
+	}
+	for header, want := range testCases {
+		if got := diffPath(header); got != want {
+			t.Errorf("diffPath(%q) = %q, want %q", header, got, want)
+		}
+	}
+}
+
+func TestChangeSet(t *testing.T) {
+	base := t.TempDir()
+	changes, err := newChangeSet(base, map[string][]lineRange{
+		"a/b/c.txt":   {{5, 5}, {1, 2}, {3, 3}, {8, 9}},
+		"deleted.txt": nil,
+	})
+	if err != nil {
+		t.Fatalf("newChangeSet() error = %v", err)
+	}
+	if want := []lineRange{{1, 3}, {5, 5}, {8, 9}}; !reflect.DeepEqual(changes.files["a/b/c.txt"], want) {
+		t.Errorf("merged ranges = %v, want %v", changes.files["a/b/c.txt"], want)
+	}
+
+	if !changes.hasFile(filepath.Join(base, "a", "b", "c.txt")) {
+		t.Error("hasFile(a/b/c.txt) = false, want true")
+	}
+	if changes.hasFile(filepath.Join(base, "deleted.txt")) {
+		t.Error("hasFile(deleted.txt) = true, want false for a file without added lines")
+	}
+	for _, dir := range []string{base, filepath.Join(base, "a"), filepath.Join(base, "a", "b")} {
+		if !changes.hasDir(dir) {
+			t.Errorf("hasDir(%s) = false, want true", dir)
+		}
+	}
+	if changes.hasDir(filepath.Join(base, "other")) {
+		t.Error("hasDir(other) = true, want false")
+	}
+
+	typos := []MisspelledWord{{Word: "a", LineNumber: 1}, {Word: "b", LineNumber: 4}, {Word: "c", LineNumber: 5}, {Word: "d", LineNumber: 10}}
+	got := changes.filter(filepath.Join(base, "a", "b", "c.txt"), typos)
+	if want := []MisspelledWord{typos[0], typos[2]}; !reflect.DeepEqual(got, want) {
+		t.Errorf("filter() = %v, want %v", got, want)
+	}
+}
+
+func TestGitChanges(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	repo := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", repo}, args...)...)
+		cmd.Env = append(os.Environ(), "GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@example.com",
+			"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@example.com", "GIT_CONFIG_GLOBAL=/dev/null")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	git("init", "-q", "-b", "main")
+	// User settings must not change the paths of the diff.
+	git("config", "diff.mnemonicPrefix", "true")
+	createFile(t, repo, "legacy.txt", "hello wrld\n")
+	createFile(t, repo, "docs/guide.txt", "hello wrld\nhello world\n")
+	git("add", ".")
+	git("commit", "-q", "-m", "base")
+	git("checkout", "-q", "-b", "feature")
+	createFile(t, repo, "docs/guide.txt", "hello wrld\nhello world\nnew tpyo\n")
+	git("commit", "-q", "-am", "feature")
+	// Uncommitted and untracked changes count too.
+	createFile(t, repo, "docs/draft.txt", "draft tpyo\n")
+
+	changes, err := GitChanges(repo, "main")
+	if err != nil {
+		t.Fatalf("GitChanges() error = %v", err)
+	}
+	want := map[string][]lineRange{
+		"docs/guide.txt": {{3, 3}},
+		"docs/draft.txt": {{1, math.MaxInt}},
+	}
+	if !reflect.DeepEqual(changes.files, want) {
+		t.Errorf("GitChanges() files = %v, want %v", changes.files, want)
+	}
+
+	// Only typos on changed lines of changed files are reported.
+	dictionary := &Dictionary{Words: map[string]struct{}{"hello": {}, "world": {}, "new": {}, "draft": {}}}
+	results, summary, err := runConcurrentChecker(context.Background(), repo, dictionary, CheckOptions{Changes: changes})
+	if err != nil {
+		t.Fatalf("runConcurrentChecker failed: %v", err)
+	}
+	wantResults := map[string][]MisspelledWord{
+		filepath.Join(repo, "docs", "draft.txt"): {{Word: "tpyo", LineNumber: 1, Column: 7}},
+		filepath.Join(repo, "docs", "guide.txt"): {{Word: "tpyo", LineNumber: 3, Column: 5}},
+	}
+	for _, typos := range results {
+		for i := range typos {
+			typos[i].Suggestions = nil
+		}
+	}
+	if !reflect.DeepEqual(results, wantResults) {
+		t.Errorf("runConcurrentChecker() = %v, want %v", results, wantResults)
+	}
+	if summary.FilesScanned != 2 {
+		t.Errorf("FilesScanned = %d, want 2", summary.FilesScanned)
+	}
+
+	// Paths are relative to the scanned directory.
+	changes, err = GitChanges(filepath.Join(repo, "docs"), "main")
+	if err != nil {
+		t.Fatalf("GitChanges(docs) error = %v", err)
+	}
+	if _, ok := changes.files["guide.txt"]; !ok {
+		t.Errorf("GitChanges(docs) files = %v, want guide.txt", changes.files)
+	}
+
+	if _, err := GitChanges(repo, "no-such-branch"); err == nil {
+		t.Error("GitChanges() error = nil, want an error for an unknown revision")
+	}
+}
@@ -0,0 +1,235 @@
+package spellchecker
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// directivePrefix starts an inline directive, e.g. "// spellchecker:disable-next-line".
+const directivePrefix = "spellchecker:"
+
+// directivePattern finds directives in the comments of a file, as kept by
+// extractComments, so they work in every comment syntax: "//", "#", "/* */" or
+// "<!-- -->", and on lines of their own in plain text.
+var directivePattern = regexp.MustCompile(`spellchecker:([a-z]+(?:-[a-z]+)*)`)
+
+// directiveEnd cuts an ignore list at the end of the comment that contains it.
+var directiveEnd = regexp.MustCompile(`\*/|-->`)
+
+type directiveKind int
+
+const (
+	// directiveIgnore accepts the listed words everywhere in the file.
+	directiveIgnore directiveKind = iota
+	// directiveDisableNextLine suppresses the typos of the following line.
+	directiveDisableNextLine
+	// directiveDisableLine suppresses the typos of its own line.
+	directiveDisableLine
+	// directiveDisable suppresses typos until the next directiveEnable, or the end of the file.
+	directiveDisable
+	// directiveEnable ends a region opened by directiveDisable.
+	directiveEnable
+)
+
+var directiveKinds = map[string]directiveKind{
+	"ignore":            directiveIgnore,
+	"disable-next-line": directiveDisableNextLine,
+	"disable-line":      directiveDisableLine,
+	"disable":           directiveDisable,
+	"enable":            directiveEnable,
+}
+
+// directive is one inline directive found in a file.
+type directive struct {
+	Kind directiveKind
+	// Text is the directive as written, e.g. "spellchecker:disable-line".
+	Text string
+	// Line is the 0-based line index and Column the 1-based rune column of Text.
+	Line   int
+	Column int
+	// End is the rune column just past the directive, including an ignore list.
+	End int
+	// Words are the words listed by an ignore directive.
+	Words []token
+	// used records whether the directive suppressed a typo; usedWords does so per
+	// listed word for ignore directives.
+	used      bool
+	usedWords []bool
+}
+
+// fileDirectives holds the directives of one file, indexed for lookups while checking.
+type fileDirectives struct {
+	all []*directive
+	// lines maps a line index to the directives that disable it.
+	lines map[int][]*directive
+	// ignored maps a normalized word to the ignore directives that list it.
+	ignored map[string][]ignoredWord
+}
+
+type ignoredWord struct {
+	directive *directive
+	index     int
+}
+
+// extractDirectiveLines keeps the lines of a plain text file that start with a
+// directive, after any indentation, and blanks the others. Plain text has no comment
+// syntax, so such a line stands for a comment; a directive later on a line is prose.
+func extractDirectiveLines(lines []string) []string {
+	out := make([]string, len(lines))
+	for i, line := range lines {
+		if strings.HasPrefix(strings.TrimLeft(line, " \t"), directivePrefix) {
+			out[i] = line
+		} else {
+			out[i] = blank(line)
+		}
+	}
+	return out
+}
+
+// parseDirectives finds the directives in the comments of a file, lines as returned by
+// extractComments, and resolves the lines and words each of them suppresses. It
+// returns nil if there are none.
+func parseDirectives(lines []string) *fileDirectives {
+	var d *fileDirectives
+	var region *directive
+	for n, line := range lines {
+		for _, loc := range directivePattern.FindAllStringSubmatchIndex(line, -1) {
+			kind, ok := directiveKinds[line[loc[2]:loc[3]]]
+			if !ok {
+				// An unknown name is left in the text, where it will be reported as a typo.
+				continue
+			}
+			if d == nil {
+				d = &fileDirectives{lines: make(map[int][]*directive), ignored: make(map[string][]ignoredWord)}
+			}
+			dir := &directive{
+				Kind:   kind,
+				Text:   line[loc[0]:loc[1]],
+				Line:   n,
+				Column: utf8.RuneCountInString(line[:loc[0]]) + 1,
+			}
+			dir.End = dir.Column + utf8.RuneCountInString(dir.Text)
+			d.all = append(d.all, dir)
+
+			switch kind {
+			case directiveIgnore:
+				rest := line[loc[1]:]
+				if end := directiveEnd.FindStringIndex(rest); end != nil {
+					rest = rest[:end[0]]
+				}
+				if next := strings.Index(rest, directivePrefix); next >= 0 {
+					rest = rest[:next]
+				}
+				offset := dir.End - 1
+				for _, word := range tokenize(rest) {
+					word.Column += offset
+					dir.Words = append(dir.Words, word)
+					dir.End = word.Column + utf8.RuneCountInString(word.Text)
+					key := normalizeWord(word.Text)
+					d.ignored[key] = append(d.ignored[key], ignoredWord{directive: dir, index: len(dir.Words) - 1})
+				}
+				dir.usedWords = make([]bool, len(dir.Words))
+			case directiveDisableLine:
+				d.lines[n] = append(d.lines[n], dir)
+			case directiveDisableNextLine:
+				d.lines[n+1] = append(d.lines[n+1], dir)
+			case directiveDisable:
+				if region == nil {
+					region = dir
+				}
+			case directiveEnable:
+				if region != nil {
+					// Closing a region is what enable is for, whether or not the region
+					// suppressed anything; an unused region is reported on its disable.
+					dir.used = true
+					for i := region.Line; i < n; i++ {
+						d.lines[i] = append(d.lines[i], region)
+					}
+					region = nil
+				}
+			}
+		}
+	}
+	if region != nil {
+		for i := region.Line; i < len(lines); i++ {
+			d.lines[i] = append(d.lines[i], region)
+		}
+	}
+	return d
+}
+
+// mask blanks the directives in the extracted lines so that their own text is never
+// spell checked. Extracted lines have the same runes as the original ones.
+func (d *fileDirectives) mask(lines []string) {
+	if d == nil {
+		return
+	}
+	for _, dir := range d.all {
+		if dir.Line >= len(lines) {
+			continue
+		}
+		runes := []rune(lines[dir.Line])
+		for i := dir.Column - 1; i < dir.End-1 && i < len(runes); i++ {
+			runes[i] = ' '
+		}
+		lines[dir.Line] = string(runes)
+	}
+}
+
+// suppresses reports whether a typo on line n is silenced by a directive, and marks
+// the directives responsible as used. words are the misspelled word and, when it is
+// part of a split identifier, the whole identifier; an ignore list may name either.
+func (d *fileDirectives) suppresses(n int, words ...string) bool {
+	if d == nil {
+		return false
+	}
+	suppressed := false
+	for _, dir := range d.lines[n] {
+		dir.used = true
+		suppressed = true
+	}
+	for _, word := range words {
+		for _, ignored := range d.ignored[normalizeWord(word)] {
+			ignored.directive.used = true
+			ignored.directive.usedWords[ignored.index] = true
+			suppressed = true
+		}
+	}
+	return suppressed
+}
+
+// unused returns a finding for every directive, and every word of an ignore list,
+// that suppressed no typo.
+func (d *fileDirectives) unused() []MisspelledWord {
+	if d == nil {
+		return nil
+	}
+	var findings []MisspelledWord
+	for _, dir := range d.all {
+		if dir.Kind == directiveIgnore {
+			for i, word := range dir.Words {
+				if !dir.usedWords[i] {
+					findings = append(findings, MisspelledWord{Word: word.Text, LineNumber: dir.Line + 1, Column: word.Column, Rule: RuleUnusedDirective})
+				}
+			}
+			if len(dir.Words) > 0 {
+				continue
+			}
+		}
+		if !dir.used {
+			findings = append(findings, MisspelledWord{Word: dir.Text, LineNumber: dir.Line + 1, Column: dir.Column, Rule: RuleUnusedDirective})
+		}
+	}
+	return findings
+}
+
+// unusedDirectiveMessage describes an unused directive, or an unused word of an
+// ignore list, given the Word of its finding.
+func unusedDirectiveMessage(word string) string {
+	if strings.HasPrefix(word, directivePrefix) {
+		return fmt.Sprintf("Unused directive \"%s\": it suppresses no typo.", word)
+	}
+	return fmt.Sprintf("Unused directive: \"%s\" is ignored but never misspelled.", word)
+}
@@ -0,0 +1,197 @@
+package spellchecker
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseDirectives(t *testing.T) {
+	lines := []string{
+		"/* spellchecker:ignore foo Bar */ baz",
+		"spellchecker:disabel",
+		"<!-- spellchecker:disable -->",
+		"text",
+		"# spellchecker:enable",
+		"# spellchecker:enable",
+		"// spellchecker:disable-next-line",
+	}
+	d := parseDirectives(lines)
+	if d == nil {
+		t.Fatal("parseDirectives() = nil; want directives")
+	}
+
+	// The misspelled name on line 2 is not a directive.
+	if len(d.all) != 5 {
+		t.Fatalf("Expected 5 directives, got %d", len(d.all))
+	}
+	ignore := d.all[0]
+	wantWords := []token{{Text: "foo", Column: 24}, {Text: "Bar", Column: 28}}
+	if ignore.Kind != directiveIgnore || !reflect.DeepEqual(ignore.Words, wantWords) || ignore.End != 31 {
+		t.Errorf("Unexpected ignore directive: %+v", ignore)
+	}
+	if _, ok := d.ignored["bar"]; !ok {
+		t.Error("Ignored words should be normalized")
+	}
+
+	// The region covers its disable line up to, not including, the enable line.
+	for n, want := range []bool{false, false, true, true, false, false, false} {
+		if got := len(d.lines[n]) > 0; got != want {
+			t.Errorf("line %d disabled = %v; want %v", n, got, want)
+		}
+	}
+	// A disable-next-line on the last line points past the end of the file.
+	if len(d.lines[7]) != 1 {
+		t.Errorf("Expected disable-next-line to cover line 7, got %v", d.lines[7])
+	}
+
+	masked := append([]string(nil), lines...)
+	d.mask(masked)
+	if masked[0] != "/*"+strings.Repeat(" ", 29)+"*/ baz" {
+		t.Errorf("mask() = %q", masked[0])
+	}
+	if masked[1] != lines[1] {
+		t.Errorf("mask() changed a line without directives: %q", masked[1])
+	}
+
+	if parseDirectives([]string{"no directives here"}) != nil {
+		t.Error("parseDirectives() should return nil without directives")
+	}
+}
+
+func TestCheckFileDirectives(t *testing.T) {
+	mockDictionary := &Dictionary{Words: map[string]struct{}{
+		"the": {}, "there": {}, "value": {}, "world": {}, "hello": {}, "some": {}, "here": {}, "call": {}, "api": {},
+	}}
+	goSource := strings.Join([]string{
+		"package main",
+		"",
+		"// spellchecker:ignore frobnicate",
+		"// frobnicate the wrld",
+		"// spellchecker:disable-next-line",
+		"// helo there",
+		"x := 1 // teh value spellchecker:disable-line",
+		"// spellchecker:disable",
+		"// anothr",
+		"// spellchecker:enable",
+		"// spellchecker:disable-line",
+		"// spellchecker:ignore unusedword",
+	}, "\n")
+
+	testCases := []struct {
+		name, fileName, content string
+		reportUnused            bool
+		want                    []MisspelledWord
+	}{
+		{
+			name:     "directives in Go comments",
+			fileName: "main.go",
+			content:  goSource,
+			want: []MisspelledWord{
+				{Word: "wrld", LineNumber: 4, Column: 19, Suggestions: []string{"world"}},
+			},
+		},
+		{
+			name:         "unused directives reported",
+			fileName:     "main.go",
+			content:      goSource,
+			reportUnused: true,
+			want: []MisspelledWord{
+				{Word: "wrld", LineNumber: 4, Column: 19, Suggestions: []string{"world"}},
+				{Word: "spellchecker:disable-line", LineNumber: 11, Column: 4, Rule: RuleUnusedDirective},
+				{Word: "unusedword", LineNumber: 12, Column: 24, Rule: RuleUnusedDirective},
+			},
+		},
+		{
+			name:     "directive in a Markdown HTML comment",
+			fileName: "README.md",
+			content:  "<!-- spellchecker:disable-next-line -->\nSome wrld here\n",
+		},
+		{
+			name:     "directive in a Markdown code fence is only an example",
+			fileName: "README.md",
+			content:  "```\n<!-- spellchecker:disable -->\n```\nSome wrld here\n",
+			want: []MisspelledWord{
+				{Word: "wrld", LineNumber: 4, Column: 6, Suggestions: []string{"world"}},
+			},
+		},
+		{
+			name:     "directive in a Markdown code span is only an example",
+			fileName: "README.md",
+			content:  "Call `<!-- spellchecker:disable -->` here\nSome wrld here\n",
+			want: []MisspelledWord{
+				{Word: "wrld", LineNumber: 2, Column: 6, Suggestions: []string{"world"}},
+			},
+		},
+		{
+			name:     "directive in a code span across lines is only an example",
+			fileName: "README.md",
+			content:  "Call ``<!--\nspellchecker:disable -->`` here\nSome wrld here\n",
+			want: []MisspelledWord{
+				{Word: "wrld", LineNumber: 3, Column: 6, Suggestions: []string{"world"}},
+			},
+		},
+		{
+			name:     "directive in a string literal is not a comment",
+			fileName: "main.go",
+			content:  "x := \"spellchecker:disable\"\n// wrld\n",
+			want: []MisspelledWord{
+				{Word: "wrld", LineNumber: 2, Column: 4, Suggestions: []string{"world"}},
+			},
+		},
+		{
+			name:     "directive on a line of its own in plain text",
+			fileName: "notes.txt",
+			content:  "  spellchecker:ignore kubelet\nspellchecker:disable-next-line\nCall teh API.\nCall the kubelet.\n",
+		},
+		{
+			name:         "plain text disable without enable runs to the end of the file",
+			fileName:     "notes.txt",
+			content:      "hello\nspellchecker:disable\nwrld\nanothr\n",
+			reportUnused: true,
+		},
+		{
+			name:     "directive later on a plain text line is prose",
+			fileName: "notes.txt",
+			content:  "Call teh API. spellchecker:disable-line\n",
+			want: []MisspelledWord{
+				{Word: "teh", LineNumber: 1, Column: 6, Suggestions: []string{"the"}},
+				{Word: "spellchecker", LineNumber: 1, Column: 15, Suggestions: []string{}},
+				{Word: "disable-line", LineNumber: 1, Column: 28, Suggestions: []string{}},
+			},
+		},
+		{
+			name:         "disable without enable runs to the end of the file",
+			fileName:     "run.sh",
+			content:      "# hello\n# spellchecker:disable\n# wrld\n# anothr\n",
+			reportUnused: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			filePath := filepath.Join(t.TempDir(), tc.fileName)
+			if err := os.WriteFile(filePath, []byte(tc.content), 0644); err != nil {
+				t.Fatalf("Failed to write test file: %v", err)
+			}
+			got, _ := checkFile(filePath, mockDictionary, CheckOptions{ReportUnusedDirectives: tc.reportUnused})
+			if len(got) == 0 && len(tc.want) == 0 {
+				return
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("checkFile() = %+v; want %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestUnusedDirectiveMessage(t *testing.T) {
+	if got := typoMessage(MisspelledWord{Word: "spellchecker:enable", Rule: RuleUnusedDirective}); got != `Unused directive "spellchecker:enable": it suppresses no typo.` {
+		t.Errorf("Unexpected message %q", got)
+	}
+	if got := typoMessage(MisspelledWord{Word: "frob", Rule: RuleUnusedDirective}); got != `Unused directive: "frob" is ignored but never misspelled.` {
+		t.Errorf("Unexpected message %q", got)
+	}
+}
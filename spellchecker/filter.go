@@ -0,0 +1,97 @@
+package spellchecker
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// pathFilter selects the files of a scan with the --include and --exclude patterns.
+// Patterns are doublestar globs (see matchGlob) matched against the slash-separated
+// path relative to the scanned directory:
+//
+//   - a pattern without a "/", such as "*.log" or "node_modules", matches the base
+//     name at any depth;
+//   - a pattern with a "/", such as "docs/legacy/**" or "/CHANGELOG.md", matches the
+//     whole relative path; a leading "/" or "./" is ignored;
+//   - a trailing "/", as in "build/", matches directories only.
+//
+// Exclude patterns apply to files and directories, and an excluded directory is not
+// walked at all. Include patterns apply to files only: when there are any, a file is
+// checked only if it matches one of them. Exclusion always wins over inclusion.
+type pathFilter struct {
+	// root is the scanned path. When it is a file, the file is matched by its name.
+	root    string
+	include []string
+	exclude []string
+}
+
+func newPathFilter(root string, include, exclude []string) pathFilter {
+	return pathFilter{root: root, include: include, exclude: exclude}
+}
+
+// excluded reports whether path, a file or a directory of the walk, matches an
+// exclude pattern.
+func (f pathFilter) excluded(path string, isDir bool) bool {
+	rel := f.relative(path)
+	for _, pattern := range f.exclude {
+		if matchPathPattern(pattern, rel, isDir) {
+			return true
+		}
+	}
+	return false
+}
+
+// included reports whether the file path should be checked according to the include
+// patterns; every file is included when there are none.
+func (f pathFilter) included(path string) bool {
+	if len(f.include) == 0 {
+		return true
+	}
+	rel := f.relative(path)
+	for _, pattern := range f.include {
+		if matchPathPattern(pattern, rel, false) {
+			return true
+		}
+	}
+	return false
+}
+
+// relative returns path relative to the scanned path, slash-separated.
+func (f pathFilter) relative(path string) string {
+	rel, err := filepath.Rel(f.root, path)
+	if err != nil || rel == "." {
+		return filepath.Base(path)
+	}
+	return filepath.ToSlash(rel)
+}
+
+// matchPathPattern reports whether pattern matches rel, a slash-separated path
+// relative to the scanned directory.
+func matchPathPattern(pattern, rel string, isDir bool) bool {
+	pattern = strings.TrimPrefix(pattern, "./")
+	if strings.HasSuffix(pattern, "/") {
+		if !isDir {
+			return false
+		}
+		pattern = strings.TrimRight(pattern, "/")
+	}
+	if strings.Contains(pattern, "/") {
+		return matchGlob(strings.TrimPrefix(pattern, "/"), rel)
+	}
+	return matchGlob(pattern, rel[strings.LastIndex(rel, "/")+1:])
+}
+
+// validatePatterns returns an error for the first malformed pattern, which would
+// otherwise silently match nothing.
+func validatePatterns(option string, patterns []string) error {
+	for _, pattern := range patterns {
+		for _, segment := range strings.Split(pattern, "/") {
+			if _, err := path.Match(segment, ""); err != nil {
+				return fmt.Errorf("invalid %s pattern %q: %w", option, pattern, err)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package spellchecker
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestMatchPathPattern(t *testing.T) {
+	testCases := []struct {
+		name    string
+		pattern string
+		rel     string
+		isDir   bool
+		want    bool
+	}{
+		{"exact match", "report.log", "report.log", false, true},
+		{"wildcard match", "*.log", "report.log", false, true},
+		{"no match", "*.log", "main.go", false, false},
+		{"directory match", "node_modules", "node_modules", true, true},
+		{"base name at any depth", "*.log", "logs/2024/report.log", false, true},
+		{"directory pattern matches directory", "build/", "build", true, true},
+		{"directory pattern skips file", "build/", "build", false, false},
+		{"directory pattern at any depth", "build/", "cmd/app/build", true, true},
+		{"path pattern is anchored", "docs/legacy/**", "docs/legacy/old.md", false, true},
+		{"path pattern matches directory itself", "docs/legacy/**", "docs/legacy", true, true},
+		{"path pattern not at any depth", "docs/legacy/**", "src/docs/legacy/old.md", false, false},
+		{"leading slash", "/CHANGELOG.md", "CHANGELOG.md", false, true},
+		{"leading slash only at root", "/CHANGELOG.md", "docs/CHANGELOG.md", false, false},
+		{"leading dot slash", "./vendor/", "vendor", true, true},
+		{"double star prefix", "**/*.md", "README.md", false, true},
+		{"double star prefix nested", "**/*.md", "docs/guide/intro.md", false, true},
+		{"double star middle", "docs/**/*.txt", "docs/a/b/c.txt", false, true},
+		{"malformed pattern", "[", "[", false, false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := matchPathPattern(tc.pattern, tc.rel, tc.isDir); got != tc.want {
+				t.Errorf("matchPathPattern(%q, %q, %v) = %v, want %v", tc.pattern, tc.rel, tc.isDir, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestValidatePatterns(t *testing.T) {
+	if err := validatePatterns("exclude", []string{"*.log", "docs/**/[a-z]*.md", "build/"}); err != nil {
+		t.Errorf("validatePatterns() error = %v, want nil", err)
+	}
+	if err := validatePatterns("exclude", []string{"*.log", "docs/[a-"}); err == nil {
+		t.Error("validatePatterns() error = nil, want an error for a malformed pattern")
+	}
+}
+
+func TestRunConcurrentCheckerIncludeExclude(t *testing.T) {
+	mockDictionary := &Dictionary{Words: map[string]struct{}{"hello": {}}}
+	root := t.TempDir()
+	createFile(t, root, "README.md", "hello wrld")
+	createFile(t, root, "notes.txt", "hello wrld")
+	createFile(t, root, "main.go", "// hello wrld")
+	createFile(t, root, "docs/guide.md", "hello wrld")
+	createFile(t, root, "docs/legacy/old.md", "hello wrld")
+	createFile(t, root, "build/out.txt", "hello wrld")
+	createFile(t, root, "src/build/keep.txt", "hello wrld")
+
+	checked := func(opts CheckOptions) ([]string, ScanSummary) {
+		_, summary, err := runConcurrentChecker(context.Background(), root, mockDictionary, opts)
+		if err != nil {
+			t.Fatalf("runConcurrentChecker failed: %v", err)
+		}
+		var files []string
+		for _, path := range summary.Files {
+			rel, _ := filepath.Rel(root, path)
+			files = append(files, filepath.ToSlash(rel))
+		}
+		sort.Strings(files)
+		return files, summary
+	}
+
+	// A path pattern skips one directory, a directory pattern every directory of that name.
+	got, _ := checked(CheckOptions{Exclude: []string{"docs/legacy/**", "build/"}})
+	want := []string{"README.md", "docs/guide.md", "main.go", "notes.txt"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Checked files with exclude = %v; want %v", got, want)
+	}
+
+	// Include restricts the check to matching files, and exclude wins over include.
+	got, summary := checked(CheckOptions{
+		Include: []string{"**/*.md", "*.txt"},
+		Exclude: []string{"docs/legacy/"},
+	})
+	want = []string{"README.md", "build/out.txt", "docs/guide.md", "notes.txt", "src/build/keep.txt"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Checked files with include = %v; want %v", got, want)
+	}
+	// main.go is not included; files inside docs/legacy are not counted.
+	if summary.FilesSkipped != 1 {
+		t.Errorf("FilesSkipped = %d; want 1", summary.FilesSkipped)
+	}
+
+	// A file named on the command line is matched by its name.
+	results, _, err := runConcurrentChecker(context.Background(), filepath.Join(root, "notes.txt"), mockDictionary, CheckOptions{Exclude: []string{"*.txt"}})
+	if err != nil {
+		t.Fatalf("runConcurrentChecker failed: %v", err)
+	}
+	if len(results) != 0 {
+		t.Errorf("Excluded file was checked: %v", results)
+	}
+}
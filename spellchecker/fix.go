@@ -0,0 +1,99 @@
+package spellchecker
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// FixEdit replaces Old, found at a 1-based line and rune column, with New.
+type FixEdit struct {
+	Line, Column int
+	Old, New     string
+}
+
+// applyEdits returns content with edits applied. Line endings are kept as they are.
+// Every edit must still find its old word at its position, so that a file changed
+// since it was checked is not corrupted.
+func applyEdits(content string, edits []FixEdit) (string, error) {
+	lines := strings.SplitAfter(content, "\n")
+	sorted := append([]FixEdit(nil), edits...)
+	// Right to left within a line, so that an edit does not move the columns of the
+	// edits still to apply.
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Line != sorted[j].Line {
+			return sorted[i].Line < sorted[j].Line
+		}
+		return sorted[i].Column > sorted[j].Column
+	})
+	for _, e := range sorted {
+		if e.Line < 1 || e.Line > len(lines) {
+			return "", fmt.Errorf("line %d no longer exists", e.Line)
+		}
+		line := lines[e.Line-1]
+		text := strings.TrimRight(line, "\r\n")
+		runes := []rune(text)
+		start, end := e.Column-1, e.Column-1+len([]rune(e.Old))
+		if start < 0 || end > len(runes) || string(runes[start:end]) != e.Old {
+			return "", fmt.Errorf("line %d changed since it was checked", e.Line)
+		}
+		lines[e.Line-1] = string(runes[:start]) + e.New + string(runes[end:]) + line[len(text):]
+	}
+	return strings.Join(lines, ""), nil
+}
+
+// writeFileAtomic replaces the contents of the existing file path with data. The data
+// is written to a temporary file in the same directory, which is then renamed over
+// path, so that the file is never left half-written. The file's permissions are kept.
+func writeFileAtomic(path string, data []byte) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), info.Mode().Perm()); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// AppendPersonalWords appends words to a personal dictionary file, one per line,
+// creating the file if needed.
+func AppendPersonalWords(path string, words []string) error {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	text := strings.Join(words, "\n") + "\n"
+	// Start on a new line if the file does not end with one.
+	if info, err := file.Stat(); err == nil && info.Size() > 0 {
+		last := make([]byte, 1)
+		if _, err := file.ReadAt(last, info.Size()-1); err == nil && last[0] != '\n' {
+			text = "\n" + text
+		}
+	}
+	if _, err := file.WriteString(text); err != nil {
+		return err
+	}
+	return file.Close()
+}
@@ -0,0 +1,72 @@
+package spellchecker
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestApplyEdits(t *testing.T) {
+	content := "naïve teh wrld\nsecond teh\n"
+	got, err := applyEdits(content, []FixEdit{
+		{Line: 1, Column: 7, Old: "teh", New: "the"},
+		{Line: 1, Column: 11, Old: "wrld", New: "world"},
+		{Line: 2, Column: 8, Old: "teh", New: "the"},
+	})
+	if err != nil {
+		t.Fatalf("applyEdits() error = %v", err)
+	}
+	if want := "naïve the world\nsecond the\n"; got != want {
+		t.Errorf("applyEdits() = %q, want %q", got, want)
+	}
+
+	for _, edit := range []FixEdit{
+		{Line: 1, Column: 1, Old: "teh", New: "the"},
+		{Line: 5, Column: 1, Old: "teh", New: "the"},
+		{Line: 2, Column: 9, Old: "teh", New: "the"},
+	} {
+		if _, err := applyEdits(content, []FixEdit{edit}); err == nil {
+			t.Errorf("applyEdits(%+v) error = nil, want an error", edit)
+		}
+	}
+}
+
+func TestWriteFileAtomic(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "script.sh")
+	os.WriteFile(path, []byte("old"), 0755)
+	if err := writeFileAtomic(path, []byte("new")); err != nil {
+		t.Fatalf("writeFileAtomic() error = %v", err)
+	}
+	got, _ := os.ReadFile(path)
+	info, _ := os.Stat(path)
+	if string(got) != "new" || info.Mode().Perm() != 0755 {
+		t.Errorf("file = %q with mode %v, want \"new\" with mode 0755", got, info.Mode().Perm())
+	}
+	entries, _ := os.ReadDir(dir)
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	if !reflect.DeepEqual(names, []string{"script.sh"}) {
+		t.Errorf("directory contains %v, want only script.sh", names)
+	}
+	if err := writeFileAtomic(filepath.Join(dir, "missing.txt"), nil); err == nil {
+		t.Error("writeFileAtomic() error = nil, want an error for a missing file")
+	}
+}
+
+func TestAppendPersonalWords(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "words.txt")
+	if err := AppendPersonalWords(path, []string{"kubelet"}); err != nil {
+		t.Fatalf("AppendPersonalWords() error = %v", err)
+	}
+	if err := AppendPersonalWords(path, []string{"etcd", "gRPC"}); err != nil {
+		t.Fatalf("AppendPersonalWords() error = %v", err)
+	}
+	got, _ := os.ReadFile(path)
+	if want := "kubelet\netcd\ngRPC\n"; string(got) != want {
+		t.Errorf("personal dictionary = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,40 @@
+package spellchecker
+
+import (
+	"path"
+	"strings"
+)
+
+// matchGlob reports whether the slash-separated path name matches pattern. "*", "?"
+// and character classes such as "[a-z]" match within one path segment, as in
+// path.Match, while a "**" segment matches any number of segments, including none:
+// "docs/**/*.md" matches "docs/a.md" and "docs/guide/intro.md". A malformed pattern
+// matches nothing.
+func matchGlob(pattern, name string) bool {
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(name, "/"))
+}
+
+func matchSegments(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			// Collapse repeated "**" segments, then try every possible split.
+			for len(pattern) > 1 && pattern[1] == "**" {
+				pattern = pattern[1:]
+			}
+			for i := 0; i <= len(name); i++ {
+				if matchSegments(pattern[1:], name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(name) == 0 {
+			return false
+		}
+		if ok, err := path.Match(pattern[0], name[0]); err != nil || !ok {
+			return false
+		}
+		pattern, name = pattern[1:], name[1:]
+	}
+	return len(name) == 0
+}
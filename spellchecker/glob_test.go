@@ -0,0 +1,31 @@
+package spellchecker
+
+import "testing"
+
+func TestMatchGlob(t *testing.T) {
+	testCases := []struct {
+		pattern, name string
+		want          bool
+	}{
+		{"*.md", "README.md", true},
+		{"*.md", "docs/README.md", false},
+		{"docs/*.md", "docs/README.md", true},
+		{"docs/*.md", "docs/guide/intro.md", false},
+		{"docs/**/*.md", "docs/README.md", true},
+		{"docs/**/*.md", "docs/guide/v1/intro.md", true},
+		{"**/*.md", "README.md", true},
+		{"**/vendor", "a/b/vendor", true},
+		{"build/**", "build/out/app.js", true},
+		{"build/**", "builder/app.js", false},
+		{"a/**/**/b", "a/b", true},
+		{"file?.txt", "file1.txt", true},
+		{"file[0-9].txt", "fileA.txt", false},
+		{"[", "[", false},
+	}
+
+	for _, tc := range testCases {
+		if got := matchGlob(tc.pattern, tc.name); got != tc.want {
+			t.Errorf("matchGlob(%q, %q) = %v; want %v", tc.pattern, tc.name, got, tc.want)
+		}
+	}
+}
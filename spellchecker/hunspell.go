@@ -0,0 +1,489 @@
+package spellchecker
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// hunspellAffix is a single prefix or suffix rule, e.g. "SFX D y ied [^aeiou]y".
+type hunspellAffix struct {
+	// Strip is removed from the stem before Add is attached.
+	Strip string
+	Add   string
+	// Condition must match the stem; nil matches every stem.
+	Condition *regexp.Regexp
+	// Continuation lists the flags of affixes that may follow this one (twofold suffixes).
+	Continuation []string
+}
+
+// hunspellAffixClass is the group of rules sharing one PFX or SFX flag.
+type hunspellAffixClass struct {
+	Prefix       bool
+	CrossProduct bool
+	Rules        []hunspellAffix
+}
+
+// hunspellAff holds the parts of an .aff file used for expanding a .dic file.
+type hunspellAff struct {
+	FlagType string
+	Latin1   bool
+	Aliases  [][]string
+	Affixes  map[string]*hunspellAffixClass
+
+	NoSuggest      string
+	Forbidden      string
+	NeedAffix      string
+	OnlyInCompound string
+	CompoundFlag   string
+	CompoundBegin  string
+	CompoundMiddle string
+	CompoundEnd    string
+	CompoundMin    int
+	CompoundMax    int
+}
+
+// compoundRules accepts words made of several dictionary words, following the
+// Hunspell COMPOUNDFLAG, COMPOUNDBEGIN, COMPOUNDMIDDLE and COMPOUNDEND flags.
+type compoundRules struct {
+	Begin, Middle, End map[string]struct{}
+	// MinLength is the minimum length of each part, in runes (COMPOUNDMIN).
+	MinLength int
+	// MaxWords is the maximum number of parts, or 0 for no limit (COMPOUNDWORDMAX).
+	MaxWords int
+}
+
+// loadHunspellDictionary reads a Hunspell dictionary from its .aff and .dic files.
+func loadHunspellDictionary(affPath, dicPath string) (*Dictionary, error) {
+	affFile, err := os.Open(affPath)
+	if err != nil {
+		return nil, fmt.Errorf("could not open affix file: %w", err)
+	}
+	defer affFile.Close()
+
+	dicFile, err := os.Open(dicPath)
+	if err != nil {
+		return nil, fmt.Errorf("could not open Hunspell dictionary: %w", err)
+	}
+	defer dicFile.Close()
+
+	return parseHunspell(affFile, dicFile)
+}
+
+// parseHunspell builds a dictionary from a Hunspell affix file and word list, expanding
+// every stem with the prefixes and suffixes its flags allow. COMPOUNDRULE, morphological
+// fields and suggestion tables (REP, MAP, KEY) are not supported and are ignored.
+func parseHunspell(affReader, dicReader io.Reader) (*Dictionary, error) {
+	aff, err := parseAffixFile(affReader)
+	if err != nil {
+		return nil, err
+	}
+
+	dictionary := newDictionary()
+	if aff.CompoundFlag != "" || aff.CompoundBegin != "" {
+		dictionary.compound = &compoundRules{
+			Begin:     make(map[string]struct{}),
+			Middle:    make(map[string]struct{}),
+			End:       make(map[string]struct{}),
+			MinLength: aff.CompoundMin,
+			MaxWords:  aff.CompoundMax,
+		}
+	}
+
+	scanner := bufio.NewScanner(dicReader)
+	first := true
+	for scanner.Scan() {
+		line := scanner.Text()
+		if aff.Latin1 {
+			line = decodeLatin1(line)
+		}
+		if first {
+			// The first line holds the approximate number of entries.
+			first = false
+			if _, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(line, "\ufeff"))); err == nil {
+				continue
+			}
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		word, flags := splitDicEntry(fields[0])
+		aff.addEntry(dictionary, word, aff.parseFlags(flags))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading Hunspell dictionary: %w", err)
+	}
+	return dictionary, nil
+}
+
+// parseAffixFile reads the directives of an .aff file.
+func parseAffixFile(reader io.Reader) (*hunspellAff, error) {
+	aff := &hunspellAff{Affixes: make(map[string]*hunspellAffixClass), CompoundMin: 3}
+	scanner := bufio.NewScanner(reader)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := scanner.Text()
+		if aff.Latin1 {
+			line = decodeLatin1(line)
+		}
+		fields := strings.Fields(strings.TrimPrefix(line, "\ufeff"))
+		if len(fields) < 2 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		switch fields[0] {
+		case "SET":
+			switch strings.ToUpper(fields[1]) {
+			case "UTF-8":
+			case "ISO8859-1", "ISO8859-15":
+				aff.Latin1 = true
+			default:
+				return nil, fmt.Errorf("affix file line %d: unsupported encoding %q", lineNumber, fields[1])
+			}
+		case "FLAG":
+			aff.FlagType = fields[1]
+		case "AF":
+			if _, err := strconv.Atoi(fields[1]); err != nil || len(aff.Aliases) > 0 {
+				aff.Aliases = append(aff.Aliases, aff.parseFlags(fields[1]))
+			} else {
+				// The first AF line holds the number of aliases; aliases are numbered from 1.
+				aff.Aliases = append(aff.Aliases, nil)
+			}
+		case "NOSUGGEST":
+			aff.NoSuggest = fields[1]
+		case "FORBIDDENWORD":
+			aff.Forbidden = fields[1]
+		case "NEEDAFFIX", "PSEUDOROOT":
+			aff.NeedAffix = fields[1]
+		case "ONLYINCOMPOUND":
+			aff.OnlyInCompound = fields[1]
+		case "COMPOUNDFLAG":
+			aff.CompoundFlag = fields[1]
+		case "COMPOUNDBEGIN":
+			aff.CompoundBegin = fields[1]
+		case "COMPOUNDMIDDLE":
+			aff.CompoundMiddle = fields[1]
+		case "COMPOUNDEND", "COMPOUNDLAST":
+			aff.CompoundEnd = fields[1]
+		case "COMPOUNDMIN":
+			n, err := strconv.Atoi(fields[1])
+			if err != nil {
+				return nil, fmt.Errorf("affix file line %d: invalid COMPOUNDMIN: %w", lineNumber, err)
+			}
+			aff.CompoundMin = max(n, 1)
+		case "COMPOUNDWORDMAX":
+			n, err := strconv.Atoi(fields[1])
+			if err != nil {
+				return nil, fmt.Errorf("affix file line %d: invalid COMPOUNDWORDMAX: %w", lineNumber, err)
+			}
+			aff.CompoundMax = n
+		case "PFX", "SFX":
+			if err := aff.parseAffixLine(fields); err != nil {
+				return nil, fmt.Errorf("affix file line %d: %w", lineNumber, err)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading affix file: %w", err)
+	}
+	return aff, nil
+}
+
+// parseAffixLine handles a PFX or SFX line: the first line for a flag is the class
+// header ("SFX D Y 4"), the following ones are rules ("SFX D y ied [^aeiou]y").
+func (aff *hunspellAff) parseAffixLine(fields []string) error {
+	if len(fields) < 4 {
+		return fmt.Errorf("malformed %s line", fields[0])
+	}
+	flag := fields[1]
+	class, ok := aff.Affixes[flag]
+	if !ok {
+		aff.Affixes[flag] = &hunspellAffixClass{Prefix: fields[0] == "PFX", CrossProduct: fields[2] == "Y"}
+		return nil
+	}
+
+	rule := hunspellAffix{Strip: zeroToEmpty(fields[2])}
+	add, cont, _ := strings.Cut(fields[3], "/")
+	rule.Add = zeroToEmpty(add)
+	if cont != "" {
+		rule.Continuation = aff.parseFlags(cont)
+	}
+	if len(fields) > 4 && fields[4] != "." {
+		cond, err := compileAffixCondition(fields[4], class.Prefix)
+		if err != nil {
+			return err
+		}
+		rule.Condition = cond
+	}
+	class.Rules = append(class.Rules, rule)
+	return nil
+}
+
+// compileAffixCondition turns an affix condition such as "[^aeiou]y" into a regular
+// expression anchored at the start (prefixes) or end (suffixes) of the stem.
+func compileAffixCondition(cond string, prefix bool) (*regexp.Regexp, error) {
+	var b strings.Builder
+	inClass := false
+	for _, r := range cond {
+		switch {
+		case r == '[' && !inClass:
+			inClass = true
+			b.WriteRune(r)
+		case r == ']' && inClass:
+			inClass = false
+			b.WriteRune(r)
+		case r == '.' && !inClass:
+			b.WriteRune(r)
+		case inClass && r == '^':
+			b.WriteRune(r)
+		default:
+			b.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	expr := "(?:" + b.String() + ")$"
+	if prefix {
+		expr = "^(?:" + b.String() + ")"
+	}
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid affix condition %q: %w", cond, err)
+	}
+	return re, nil
+}
+
+// parseFlags splits a flag field according to the FLAG directive. With AF aliases,
+// a numeric field refers to an alias instead.
+func (aff *hunspellAff) parseFlags(field string) []string {
+	if len(aff.Aliases) > 0 {
+		if n, err := strconv.Atoi(field); err == nil && n > 0 && n < len(aff.Aliases) {
+			return aff.Aliases[n]
+		}
+	}
+	var flags []string
+	switch aff.FlagType {
+	case "long":
+		runes := []rune(field)
+		for i := 0; i+1 < len(runes); i += 2 {
+			flags = append(flags, string(runes[i:i+2]))
+		}
+	case "num":
+		for _, f := range strings.Split(field, ",") {
+			if f = strings.TrimSpace(f); f != "" {
+				flags = append(flags, f)
+			}
+		}
+	default:
+		for _, r := range field {
+			flags = append(flags, string(r))
+		}
+	}
+	return flags
+}
+
+// affixForm is a word produced from a stem, remembering how it was produced.
+type affixForm struct {
+	Word     string
+	Prefixed bool
+	Suffixed bool
+	// CrossProduct reports whether a prefix may still be combined with this suffixed form.
+	CrossProduct bool
+}
+
+// addEntry expands one .dic entry and adds the resulting words to the dictionary.
+func (aff *hunspellAff) addEntry(dictionary *Dictionary, word string, flags []string) {
+	has := func(flag string) bool {
+		if flag == "" {
+			return false
+		}
+		for _, f := range flags {
+			if f == flag {
+				return true
+			}
+		}
+		return false
+	}
+
+	if has(aff.Forbidden) {
+		key := normalizeWord(word)
+		dictionary.Forbidden[key] = struct{}{}
+		delete(dictionary.Words, key)
+		return
+	}
+
+	forms := aff.expand(word, flags)
+	for i, form := range forms {
+		if has(aff.OnlyInCompound) || i == 0 && has(aff.NeedAffix) {
+			// forms[0] is the bare stem.
+			continue
+		}
+		key := normalizeWord(form.Word)
+		if _, forbidden := dictionary.Forbidden[key]; forbidden {
+			continue
+		}
+		dictionary.Words[key] = struct{}{}
+		if has(aff.NoSuggest) {
+			dictionary.NoSuggest[key] = struct{}{}
+		}
+	}
+
+	if c := dictionary.compound; c != nil {
+		for _, form := range forms {
+			key := normalizeWord(form.Word)
+			if has(aff.CompoundFlag) {
+				c.Begin[key], c.Middle[key], c.End[key] = struct{}{}, struct{}{}, struct{}{}
+				continue
+			}
+			if has(aff.CompoundBegin) && !form.Suffixed {
+				c.Begin[key] = struct{}{}
+			}
+			if has(aff.CompoundMiddle) && !form.Prefixed && !form.Suffixed {
+				c.Middle[key] = struct{}{}
+			}
+			if has(aff.CompoundEnd) && !form.Prefixed {
+				c.End[key] = struct{}{}
+			}
+		}
+	}
+}
+
+// expand returns the stem and every word its affix flags produce: suffixed forms
+// (including one level of continuation suffixes), prefixed forms, and, for
+// cross-product classes, forms with both a prefix and a suffix.
+func (aff *hunspellAff) expand(word string, flags []string) []affixForm {
+	forms := []affixForm{{Word: word}}
+
+	var suffixed []affixForm
+	for _, flag := range flags {
+		class := aff.Affixes[flag]
+		if class == nil || class.Prefix {
+			continue
+		}
+		for _, rule := range class.Rules {
+			derived, ok := applyAffix(word, rule, false)
+			if !ok {
+				continue
+			}
+			suffixed = append(suffixed, affixForm{Word: derived, Suffixed: true, CrossProduct: class.CrossProduct})
+			for _, contFlag := range rule.Continuation {
+				cont := aff.Affixes[contFlag]
+				if cont == nil || cont.Prefix {
+					continue
+				}
+				for _, contRule := range cont.Rules {
+					if twice, ok := applyAffix(derived, contRule, false); ok {
+						suffixed = append(suffixed, affixForm{Word: twice, Suffixed: true, CrossProduct: class.CrossProduct && cont.CrossProduct})
+					}
+				}
+			}
+		}
+	}
+	forms = append(forms, suffixed...)
+
+	for _, flag := range flags {
+		class := aff.Affixes[flag]
+		if class == nil || !class.Prefix {
+			continue
+		}
+		for _, rule := range class.Rules {
+			if derived, ok := applyAffix(word, rule, true); ok {
+				forms = append(forms, affixForm{Word: derived, Prefixed: true})
+			}
+			if !class.CrossProduct {
+				continue
+			}
+			for _, form := range suffixed {
+				if !form.CrossProduct {
+					continue
+				}
+				if derived, ok := applyAffix(form.Word, rule, true); ok {
+					forms = append(forms, affixForm{Word: derived, Prefixed: true, Suffixed: true})
+				}
+			}
+		}
+	}
+	return forms
+}
+
+// applyAffix applies a prefix or suffix rule to a stem, reporting false if the rule's
+// condition or strip string does not match.
+func applyAffix(stem string, rule hunspellAffix, prefix bool) (string, bool) {
+	if rule.Condition != nil && !rule.Condition.MatchString(stem) {
+		return "", false
+	}
+	if prefix {
+		if !strings.HasPrefix(stem, rule.Strip) || len(rule.Strip) >= len(stem) && rule.Strip != "" {
+			return "", false
+		}
+		return rule.Add + stem[len(rule.Strip):], true
+	}
+	if !strings.HasSuffix(stem, rule.Strip) || len(rule.Strip) >= len(stem) && rule.Strip != "" {
+		return "", false
+	}
+	return stem[:len(stem)-len(rule.Strip)] + rule.Add, true
+}
+
+// accepts reports whether word can be split into a valid sequence of compound parts.
+func (c *compoundRules) accepts(word string) bool {
+	return c.matchFrom([]rune(word), 0)
+}
+
+// matchFrom reports whether runes can be split into compound parts, given that
+// parts words have already been matched before it.
+func (c *compoundRules) matchFrom(runes []rune, parts int) bool {
+	set := c.Middle
+	if parts == 0 {
+		set = c.Begin
+	}
+	for i := c.MinLength; i <= len(runes)-c.MinLength; i++ {
+		if _, ok := set[string(runes[:i])]; !ok {
+			continue
+		}
+		tail := runes[i:]
+		if c.MaxWords == 0 || parts+2 <= c.MaxWords {
+			if _, ok := c.End[string(tail)]; ok {
+				return true
+			}
+		}
+		if (c.MaxWords == 0 || parts+2 < c.MaxWords) && c.matchFrom(tail, parts+1) {
+			return true
+		}
+	}
+	return false
+}
+
+// splitDicEntry splits a .dic field such as "work/DGS" into the word and its flags.
+// An escaped slash ("\/") is part of the word.
+func splitDicEntry(field string) (string, string) {
+	for i := 0; i < len(field); i++ {
+		if field[i] == '\\' && i+1 < len(field) && field[i+1] == '/' {
+			i++
+			continue
+		}
+		if field[i] == '/' {
+			return strings.ReplaceAll(field[:i], `\/`, "/"), field[i+1:]
+		}
+	}
+	return strings.ReplaceAll(field, `\/`, "/"), ""
+}
+
+// zeroToEmpty maps the Hunspell placeholder "0" to an empty strip or add string.
+func zeroToEmpty(s string) string {
+	if s == "0" {
+		return ""
+	}
+	return s
+}
+
+// decodeLatin1 converts an ISO 8859-1 line, read as raw bytes, to UTF-8.
+func decodeLatin1(s string) string {
+	runes := make([]rune, len(s))
+	for i := 0; i < len(s); i++ {
+		runes[i] = rune(s[i])
+	}
+	return string(runes)
+}
@@ -0,0 +1,144 @@
+package spellchecker
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testAff = `# Test affix file
+SET UTF-8
+NOSUGGEST !
+FORBIDDENWORD *
+NEEDAFFIX ?
+COMPOUNDFLAG C
+COMPOUNDMIN 3
+
+SFX S Y 3
+SFX S 0 s [^sy]
+SFX S y ies [^aeiou]y
+SFX S 0 es s
+
+SFX D Y 3
+SFX D 0 ed [^ey]
+SFX D y ied [^aeiou]y
+SFX D 0 d e
+
+SFX N N 1
+SFX N y iness/S [^aeiou]y
+
+PFX U Y 1
+PFX U 0 un .
+`
+
+const testDic = `8
+work/SD
+try/SD
+bake/D
+happy/UN
+shit/!
+colour/*
+unkind/?U
+foot/C
+ball/CS
+`
+
+func TestParseHunspell(t *testing.T) {
+	dict, err := parseHunspell(strings.NewReader(testAff), strings.NewReader(testDic))
+	if err != nil {
+		t.Fatalf("parseHunspell failed: %v", err)
+	}
+
+	accepted := []string{
+		"work", "works", "worked", // plain suffixes
+		"try", "tries", "tried", // condition [^aeiou]y selects "ies"
+		"bake", "baked", // condition e selects "d"
+		"happy", "unhappy", "happiness", "happinesses", // prefix, and twofold suffix via continuation flag
+		"shit",                              // NOSUGGEST words are still correct
+		"football", "ballfoot", "footballs", // compounds
+	}
+	for _, word := range accepted {
+		if !isWordCorrect(word, dict) {
+			t.Errorf("Expected %q to be accepted", word)
+		}
+	}
+
+	rejected := []string{
+		"tryed", "bakeed", // conditions exclude these forms
+		"colour", // FORBIDDENWORD
+		"unkind", // NEEDAFFIX: the stem alone is not a word
+		"unfoot", // prefix not allowed on foot
+		"foo",    // too short to be a compound part
+	}
+	for _, word := range rejected {
+		if isWordCorrect(word, dict) {
+			t.Errorf("Expected %q to be rejected", word)
+		}
+	}
+
+	if _, ok := dict.NoSuggest["shit"]; !ok {
+		t.Error("Expected 'shit' to be marked NOSUGGEST")
+	}
+	for _, s := range generateSuggestions("shat", dict, SuggestionOptions{}) {
+		if s == "shit" {
+			t.Error("NOSUGGEST word was offered as a suggestion")
+		}
+	}
+}
+
+func TestParseHunspellUnsupportedEncoding(t *testing.T) {
+	_, err := parseHunspell(strings.NewReader("SET KOI8-R\n"), strings.NewReader("1\nword\n"))
+	if err == nil {
+		t.Fatal("Expected an error for an unsupported encoding, but got nil")
+	}
+}
+
+func TestParseHunspellLongFlagsAndAliases(t *testing.T) {
+	aff := "FLAG long\nAF 2\nAF SsDd\nAF Ss\nSFX Ss Y 1\nSFX Ss 0 s .\nSFX Dd Y 1\nSFX Dd 0 ed .\n"
+	dic := "3\nwalk/1\njump/2\nlook/Dd\n"
+	dict, err := parseHunspell(strings.NewReader(aff), strings.NewReader(dic))
+	if err != nil {
+		t.Fatalf("parseHunspell failed: %v", err)
+	}
+	for _, word := range []string{"walks", "walked", "jumps", "looked"} {
+		if !isWordCorrect(word, dict) {
+			t.Errorf("Expected %q to be accepted", word)
+		}
+	}
+	for _, word := range []string{"jumped", "looks"} {
+		if isWordCorrect(word, dict) {
+			t.Errorf("Expected %q to be rejected", word)
+		}
+	}
+}
+
+func TestParseHunspellLatin1(t *testing.T) {
+	aff := "SET ISO8859-1\nSFX S Y 1\nSFX S 0 s .\n"
+	dic := "1\ncaf\xe9/S\n"
+	dict, err := parseHunspell(strings.NewReader(aff), strings.NewReader(dic))
+	if err != nil {
+		t.Fatalf("parseHunspell failed: %v", err)
+	}
+	if !isWordCorrect("cafés", dict) {
+		t.Error("Expected 'cafés' to be accepted from an ISO 8859-1 dictionary")
+	}
+}
+
+func TestLoadDictionaryHunspellPair(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "en_TEST.aff"), []byte(testAff), 0644); err != nil {
+		t.Fatalf("Failed to write affix file: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "en_TEST.dic"), []byte(testDic), 0644); err != nil {
+		t.Fatalf("Failed to write dictionary file: %v", err)
+	}
+
+	dict, err := LoadDictionary(filepath.Join(dir, "en_TEST.dic"))
+	if err != nil {
+		t.Fatalf("LoadDictionary failed: %v", err)
+	}
+	if !isWordCorrect("worked", dict) {
+		t.Error("Expected 'worked' to be accepted")
+	}
+}
@@ -0,0 +1,210 @@
+package spellchecker
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ignoreFileNames are read in every directory of the walk, in increasing precedence:
+// a .spellcheckignore can re-include, with "!", a file its .gitignore ignores.
+var ignoreFileNames = []string{".gitignore", ".spellcheckignore"}
+
+// ignorePattern is one line of an ignore file, in .gitignore syntax.
+type ignorePattern struct {
+	// base is the slash-separated directory of the ignore file, relative to the root
+	// of the matcher; "" for the root itself.
+	base string
+	// glob is matched against the path relative to base when anchored, and against the
+	// base name at any depth otherwise.
+	glob     string
+	anchored bool
+	negate   bool
+	dirOnly  bool
+}
+
+// ignoreMatcher decides which paths of a walk are ignored by .gitignore-style files.
+// Patterns are kept in the order they were read and the last matching one wins, so
+// files read later, i.e. deeper in the tree, take precedence. It is not safe for
+// concurrent use; the walk goroutine owns it.
+type ignoreMatcher struct {
+	// root is the absolute directory patterns are relative to: the root of the Git
+	// repository containing the scanned directory, or the scanned directory itself.
+	root     string
+	patterns []ignorePattern
+}
+
+// newIgnoreMatcher returns a matcher for a walk of the directory scanRoot. When
+// scanRoot is inside a Git repository, the repository's .git/info/exclude and the
+// ignore files of the directories above scanRoot are read as well.
+func newIgnoreMatcher(scanRoot string) (*ignoreMatcher, error) {
+	abs, err := filepath.Abs(scanRoot)
+	if err != nil {
+		return nil, err
+	}
+	m := &ignoreMatcher{root: abs}
+	repo := findRepositoryRoot(abs)
+	if repo == "" {
+		return m, nil
+	}
+
+	m.root = repo
+	if err := m.loadFile(filepath.Join(repo, ".git", "info", "exclude"), ""); err != nil {
+		return nil, err
+	}
+	// Ignore files from the repository root down to, not including, scanRoot, which
+	// the walk loads itself.
+	rel, err := filepath.Rel(repo, abs)
+	if err != nil {
+		return nil, err
+	}
+	dir := repo
+	if rel != "." {
+		for _, name := range strings.Split(rel, string(filepath.Separator)) {
+			if err := m.loadDir(dir); err != nil {
+				return nil, err
+			}
+			dir = filepath.Join(dir, name)
+		}
+	}
+	return m, nil
+}
+
+// findRepositoryRoot returns the closest directory at or above dir that contains a
+// .git entry, or "" if there is none.
+func findRepositoryRoot(dir string) string {
+	for {
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			return dir
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// loadDir reads the ignore files of dir, if any.
+func (m *ignoreMatcher) loadDir(dir string) error {
+	base, err := m.relative(dir)
+	if err != nil {
+		return err
+	}
+	if base == "." {
+		base = ""
+	}
+	for _, name := range ignoreFileNames {
+		if err := m.loadFile(filepath.Join(dir, name), base); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadFile reads the patterns of one ignore file; a missing file is not an error.
+func (m *ignoreMatcher) loadFile(path, base string) error {
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	patterns, err := parseIgnorePatterns(file, base)
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", path, err)
+	}
+	m.patterns = append(m.patterns, patterns...)
+	return nil
+}
+
+// parseIgnorePatterns parses .gitignore syntax: blank lines and "#" comments are
+// skipped, "!" negates, a trailing "/" matches directories only, and a pattern with a
+// "/" at the start or in the middle is anchored to the directory of the file.
+func parseIgnorePatterns(r io.Reader, base string) ([]ignorePattern, error) {
+	var patterns []ignorePattern
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSuffix(scanner.Text(), "\r")
+		// Trailing spaces are dropped unless escaped with a backslash.
+		for strings.HasSuffix(line, " ") && !strings.HasSuffix(line, "\\ ") {
+			line = line[:len(line)-1]
+		}
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		p := ignorePattern{base: base}
+		if strings.HasPrefix(line, "!") {
+			p.negate = true
+			line = line[1:]
+		}
+		if strings.HasSuffix(line, "/") {
+			p.dirOnly = true
+			line = strings.TrimRight(line, "/")
+		}
+		if strings.Contains(line, "/") {
+			p.anchored = true
+			line = strings.TrimPrefix(line, "/")
+		}
+		if line == "" {
+			continue
+		}
+		p.glob = line
+		patterns = append(patterns, p)
+	}
+	return patterns, scanner.Err()
+}
+
+// ignored reports whether path, a file or a directory below the matcher's root,
+// is ignored.
+func (m *ignoreMatcher) ignored(path string, isDir bool) bool {
+	rel, err := m.relative(path)
+	if err != nil || rel == "." || strings.HasPrefix(rel, "../") {
+		return false
+	}
+	ignored := false
+	for _, p := range m.patterns {
+		if p.negate == !ignored || (p.dirOnly && !isDir) {
+			// The pattern could not change the outcome.
+			continue
+		}
+		if p.matches(rel) {
+			ignored = !p.negate
+		}
+	}
+	return ignored
+}
+
+// matches reports whether the pattern matches rel, a slash-separated path relative
+// to the matcher's root.
+func (p ignorePattern) matches(rel string) bool {
+	if p.base != "" {
+		if !strings.HasPrefix(rel, p.base+"/") {
+			return false
+		}
+		rel = rel[len(p.base)+1:]
+	}
+	if p.anchored {
+		return matchGlob(p.glob, rel)
+	}
+	return matchGlob(p.glob, rel[strings.LastIndex(rel, "/")+1:])
+}
+
+// relative returns path relative to the matcher's root, slash-separated.
+func (m *ignoreMatcher) relative(path string) (string, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	rel, err := filepath.Rel(m.root, abs)
+	if err != nil {
+		return "", err
+	}
+	return filepath.ToSlash(rel), nil
+}
@@ -0,0 +1,136 @@
+package spellchecker
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestParseIgnorePatterns(t *testing.T) {
+	content := strings.Join([]string{
+		"# comment",
+		"",
+		"*.log   ",
+		"!keep.log",
+		"/build",
+		"docs/generated/",
+		"node_modules/",
+		"**/tmp",
+		`\#notes`,
+		`trailing\ `,
+	}, "\n")
+
+	got, err := parseIgnorePatterns(strings.NewReader(content), "sub")
+	if err != nil {
+		t.Fatalf("parseIgnorePatterns failed: %v", err)
+	}
+	want := []ignorePattern{
+		{base: "sub", glob: "*.log"},
+		{base: "sub", glob: "keep.log", negate: true},
+		{base: "sub", glob: "build", anchored: true},
+		{base: "sub", glob: "docs/generated", anchored: true, dirOnly: true},
+		{base: "sub", glob: "node_modules", dirOnly: true},
+		{base: "sub", glob: "**/tmp", anchored: true},
+		{base: "sub", glob: `\#notes`},
+		{base: "sub", glob: `trailing\ `},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseIgnorePatterns() =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestIgnoreMatcher(t *testing.T) {
+	root := t.TempDir()
+	m := &ignoreMatcher{root: root}
+	root_, _ := parseIgnorePatterns(strings.NewReader("*.log\n!keep.log\n/build\nnode_modules/\ndocs/**/draft-*\n"), "")
+	nested, _ := parseIgnorePatterns(strings.NewReader("!debug.log\nlocal.txt\n"), "pkg")
+	m.patterns = append(root_, nested...)
+
+	testCases := []struct {
+		path  string
+		isDir bool
+		want  bool
+	}{
+		{"app.log", false, true},
+		{"src/app.log", false, true},
+		{"keep.log", false, false},
+		{"build", true, true},
+		{"src/build", true, false},
+		{"node_modules", true, true},
+		{"node_modules", false, false},
+		{"src/node_modules", true, true},
+		{"docs/draft-1.md", false, true},
+		{"docs/guide/draft-2.md", false, true},
+		{"docs/guide/final.md", false, false},
+		{"pkg/debug.log", false, false},
+		{"pkg/other.log", false, true},
+		{"pkg/local.txt", false, true},
+		{"local.txt", false, false},
+	}
+	for _, tc := range testCases {
+		path := filepath.Join(root, filepath.FromSlash(tc.path))
+		if got := m.ignored(path, tc.isDir); got != tc.want {
+			t.Errorf("ignored(%q, dir=%v) = %v; want %v", tc.path, tc.isDir, got, tc.want)
+		}
+	}
+	if m.ignored(root, true) {
+		t.Error("The root itself must never be ignored")
+	}
+}
+
+func TestRunConcurrentCheckerIgnoreFiles(t *testing.T) {
+	mockDictionary := &Dictionary{Words: map[string]struct{}{"hello": {}}}
+	repo := t.TempDir()
+	createFile(t, repo, ".git/info/exclude", "secret.txt\n")
+	createFile(t, repo, ".git/HEAD", "wrld")
+	createFile(t, repo, ".gitignore", "# Dependencies and generated filez\nnode_modules/\n*.gen.txt\n/dist\n")
+	createFile(t, repo, ".spellcheckignore", "!keep.gen.txt\n")
+	createFile(t, repo, "docs/.gitignore", "drafts/\n")
+	createFile(t, repo, "docs/guide.txt", "hello wrld")
+	createFile(t, repo, "docs/drafts/todo.txt", "wrld")
+	createFile(t, repo, "docs/secret.txt", "wrld")
+	createFile(t, repo, "node_modules/pkg/readme.txt", "wrld")
+	createFile(t, repo, "dist/app.txt", "wrld")
+	createFile(t, repo, "src/dist/notes.txt", "hello wrld")
+	createFile(t, repo, "api.gen.txt", "wrld")
+	createFile(t, repo, "keep.gen.txt", "hello wrld")
+
+	checked := func(root string, opts CheckOptions) []string {
+		results, _, err := runConcurrentChecker(context.Background(), root, mockDictionary, opts)
+		if err != nil {
+			t.Fatalf("runConcurrentChecker failed: %v", err)
+		}
+		var files []string
+		for path := range results {
+			rel, _ := filepath.Rel(repo, path)
+			files = append(files, filepath.ToSlash(rel))
+		}
+		sort.Strings(files)
+		return files
+	}
+
+	// Only the comments of ignore files are checked.
+	want := []string{".gitignore", "docs/guide.txt", "keep.gen.txt", "src/dist/notes.txt"}
+	if got := checked(repo, CheckOptions{}); !reflect.DeepEqual(got, want) {
+		t.Errorf("Checked files with typos = %v; want %v", got, want)
+	}
+
+	// Scanning a subdirectory still applies the ignore files above it.
+	if got := checked(filepath.Join(repo, "docs"), CheckOptions{}); !reflect.DeepEqual(got, []string{"docs/guide.txt"}) {
+		t.Errorf("Checked files with typos in docs = %v; want [docs/guide.txt]", got)
+	}
+
+	// With NoIgnore, only .git is still skipped.
+	got := checked(repo, CheckOptions{NoIgnore: true})
+	for _, path := range got {
+		if strings.HasPrefix(path, ".git/") {
+			t.Errorf("File in .git was checked: %s", path)
+		}
+	}
+	if len(got) != 9 {
+		t.Errorf("Expected 9 files with typos without ignore files, got %v", got)
+	}
+}
@@ -0,0 +1,142 @@
+package spellchecker
+
+import (
+	"sort"
+	"unicode/utf8"
+)
+
+// wordTrie is a prefix tree of dictionary words searched with the Levenshtein dynamic
+// programming table computed one row per trie level: words sharing a prefix share
+// its rows, and a whole subtree is skipped as soon as every entry in the current row
+// exceeds the maximum distance. It is built once and is safe for concurrent searches.
+type wordTrie struct {
+	root  trieNode
+	size  int
+	depth int
+}
+
+type trieNode struct {
+	// word is set when a dictionary word ends at this node.
+	word     string
+	children []trieEdge
+}
+
+type trieEdge struct {
+	r    rune
+	node *trieNode
+}
+
+// newWordTrie builds a trie from words. Words are inserted in sorted order so the
+// order of search results does not depend on map iteration order.
+func newWordTrie(words []string) *wordTrie {
+	sorted := append([]string(nil), words...)
+	sort.Strings(sorted)
+	trie := &wordTrie{}
+	for _, word := range sorted {
+		trie.insert(word)
+	}
+	return trie
+}
+
+// insert adds word to the trie; duplicates are ignored.
+func (t *wordTrie) insert(word string) {
+	node := &t.root
+	for _, r := range word {
+		next := node.child(r)
+		if next == nil {
+			next = &trieNode{}
+			node.children = append(node.children, trieEdge{r: r, node: next})
+		}
+		node = next
+	}
+	if node.word == "" {
+		node.word = word
+		t.size++
+		t.depth = max(t.depth, utf8.RuneCountInString(word))
+	}
+}
+
+// child returns the child reached through r, or nil.
+func (n *trieNode) child(r rune) *trieNode {
+	for _, edge := range n.children {
+		if edge.r == r {
+			return edge.node
+		}
+	}
+	return nil
+}
+
+// search calls visit, in lexical order, for every word within maxDistance of word.
+// With transpositions set, distances are optimal string alignment distances
+// (see osaDistance); otherwise they are Levenshtein distances.
+func (t *wordTrie) search(word string, maxDistance int, transpositions bool, visit func(match string, distance int)) {
+	s := &trieSearch{target: []rune(word), maxDistance: maxDistance, transpositions: transpositions, visit: visit}
+	// rows[i] holds the table row for the trie node at depth i; it is reused by siblings.
+	s.rows = make([][]int, t.depth+1)
+	for i := range s.rows {
+		s.rows[i] = make([]int, len(s.target)+1)
+	}
+	for j := range s.rows[0] {
+		s.rows[0][j] = j
+	}
+	s.walk(&t.root, 0, 0)
+}
+
+// trieSearch holds the state of one search so that no allocation happens per node.
+type trieSearch struct {
+	target         []rune
+	maxDistance    int
+	transpositions bool
+	visit          func(match string, distance int)
+	rows           [][]int
+}
+
+// walk visits the children of node, whose table row is s.rows[depth] and which is
+// reached through the rune last (unused at the root).
+func (s *trieSearch) walk(node *trieNode, depth int, last rune) {
+	prev := s.rows[depth]
+	for _, edge := range node.children {
+		row := s.rows[depth+1]
+		row[0] = prev[0] + 1
+		rowMin := row[0]
+		for j := 1; j <= len(s.target); j++ {
+			cost := 1
+			if s.target[j-1] == edge.r {
+				cost = 0
+			}
+			row[j] = min(prev[j]+1, row[j-1]+1, prev[j-1]+cost)
+			if s.transpositions && depth > 0 && j > 1 && edge.r == s.target[j-2] && last == s.target[j-1] {
+				if swapped := s.rows[depth-1][j-2] + 1; swapped < row[j] {
+					row[j] = swapped
+				}
+			}
+			if row[j] < rowMin {
+				rowMin = row[j]
+			}
+		}
+		if edge.node.word != "" && row[len(s.target)] <= s.maxDistance {
+			s.visit(edge.node.word, row[len(s.target)])
+		}
+		if rowMin <= s.maxDistance {
+			s.walk(edge.node, depth+1, edge.r)
+		}
+	}
+}
+
+// BuildIndex builds the suggestion index over the dictionary's suggestible words.
+// It must be called after all words have been added, e.g. after merging the personal
+// dictionary; words added later are accepted by the checker but not suggested.
+func (d *Dictionary) BuildIndex() {
+	d.index = newWordTrie(suggestibleWords(d))
+}
+
+// suggestibleWords returns the dictionary words that are not marked NoSuggest.
+func suggestibleWords(d *Dictionary) []string {
+	words := make([]string, 0, len(d.Words))
+	for word := range d.Words {
+		if _, skip := d.NoSuggest[word]; !skip {
+			words = append(words, word)
+		}
+	}
+	return words
+}
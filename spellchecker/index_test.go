@@ -0,0 +1,105 @@
+package spellchecker
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+// syntheticDictionary returns a reproducible dictionary of n pseudo-random words.
+func syntheticDictionary(n int) *Dictionary {
+	rng := rand.New(rand.NewSource(1))
+	const letters = "etaoinshrdlcumwfgypbvkjxqz"
+	dictionary := newDictionary()
+	for len(dictionary.Words) < n {
+		word := make([]byte, 3+rng.Intn(8))
+		for i := range word {
+			// Favour frequent letters so that words have close neighbours, as in real text.
+			word[i] = letters[int(float64(len(letters))*rng.Float64()*rng.Float64())]
+		}
+		dictionary.Words[string(word)] = struct{}{}
+	}
+	return dictionary
+}
+
+func TestIndexMatchesBruteForce(t *testing.T) {
+	dictionary := syntheticDictionary(5000)
+	queries := []string{"tea", "shore", "hello", "eatonis", "zzz", "a", "toinshrdl", "hte", "seroht"}
+
+	indexed := syntheticDictionary(5000)
+	indexed.BuildIndex()
+
+	for _, metric := range []string{MetricDamerau, MetricLevenshtein} {
+		opts := SuggestionOptions{Metric: metric}
+		for _, query := range queries {
+			t.Run(metric+"/"+query, func(t *testing.T) {
+				want := generateSuggestions(query, dictionary, opts)
+				got := generateSuggestions(query, indexed, opts)
+				if !reflect.DeepEqual(got, want) {
+					t.Errorf("indexed suggestions for %q = %v; brute force = %v", query, got, want)
+				}
+			})
+		}
+	}
+}
+
+func TestWordTrieSearch(t *testing.T) {
+	trie := newWordTrie([]string{"book", "books", "cake", "boo", "cape", "cart", "boon", "book"})
+	if trie.size != 7 {
+		t.Errorf("Expected 7 distinct words in the trie, but got %d", trie.size)
+	}
+
+	var got []string
+	distances := map[string]int{}
+	trie.search("book", 1, false, func(match string, distance int) {
+		got = append(got, match)
+		distances[match] = distance
+	})
+	// Results come in lexical order.
+	want := []string{"boo", "book", "books", "boon"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("search(\"book\", 1) = %v; want %v", got, want)
+	}
+	wantDistances := map[string]int{"boo": 1, "book": 0, "books": 1, "boon": 1}
+	if !reflect.DeepEqual(distances, wantDistances) {
+		t.Errorf("search(\"book\", 1) distances = %v; want %v", distances, wantDistances)
+	}
+}
+
+func TestBuildIndexSkipsNoSuggest(t *testing.T) {
+	dictionary := &Dictionary{
+		Words:     map[string]struct{}{"shit": {}, "shot": {}},
+		NoSuggest: map[string]struct{}{"shit": {}},
+	}
+	dictionary.BuildIndex()
+	if got := generateSuggestions("shat", dictionary, SuggestionOptions{}); !reflect.DeepEqual(got, []string{"shot"}) {
+		t.Errorf("generateSuggestions(\"shat\") = %v; want [shot]", got)
+	}
+}
+
+var benchmarkQueries = []string{"thes", "recieve", "hellp", "aoinst", "tnhse", "serontia", "qzx", "dlcumw"}
+
+func BenchmarkGenerateSuggestionsBruteForce(b *testing.B) {
+	dictionary := syntheticDictionary(50000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		generateSuggestions(benchmarkQueries[i%len(benchmarkQueries)], dictionary, SuggestionOptions{})
+	}
+}
+
+func BenchmarkGenerateSuggestionsIndexed(b *testing.B) {
+	dictionary := syntheticDictionary(50000)
+	dictionary.BuildIndex()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		generateSuggestions(benchmarkQueries[i%len(benchmarkQueries)], dictionary, SuggestionOptions{})
+	}
+}
+
+func BenchmarkBuildIndex(b *testing.B) {
+	dictionary := syntheticDictionary(50000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		dictionary.BuildIndex()
+	}
+}
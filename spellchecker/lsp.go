@@ -0,0 +1,515 @@
+package spellchecker
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// lspAddToDictionary is the command of the "add to personal dictionary" code action.
+const lspAddToDictionary = "spellchecker.addToDictionary"
+
+// JSON-RPC error codes.
+const (
+	lspParseError     = -32700
+	lspMethodNotFound = -32601
+	lspInvalidParams  = -32602
+	lspInternalError  = -32603
+)
+
+// lspSeverities are the diagnostic severities of each rule: 1 is an error, 2 a warning
+// and 3 an information.
+var lspSeverities = map[string]int{
+	RuleUnknownWord:     2,
+	RuleForbiddenWord:   1,
+	RuleUnusedDirective: 3,
+}
+
+// lspServer implements the part of the Language Server Protocol an editor needs to
+// show typos as diagnostics while typing: full document sync, quick fixes for the
+// suggestions, and a command adding a word to the personal dictionary. Messages are
+// handled one at a time, in the order they arrive.
+type lspServer struct {
+	in  *bufio.Reader
+	out io.Writer
+
+	dictionary *Dictionary
+	opts       CheckOptions
+	// personal is the personal dictionary file that words are added to, if any.
+	personal string
+
+	// documents maps the URI of each open document to its last check.
+	documents map[string]*lspDocument
+	shutdown  bool
+}
+
+// lspDocument is an open document and its typos.
+type lspDocument struct {
+	path  string
+	lines []string
+	typos []MisspelledWord
+}
+
+// runLSP serves the Language Server Protocol on in and out until the client exits.
+func runLSP(in io.Reader, out io.Writer, dictionary *Dictionary, opts CheckOptions, personal string) error {
+	s := &lspServer{
+		in:         bufio.NewReader(in),
+		out:        out,
+		dictionary: dictionary,
+		opts:       opts,
+		personal:   personal,
+		documents:  make(map[string]*lspDocument),
+	}
+	return s.run()
+}
+
+// --- JSON-RPC messages ---
+
+type lspRequest struct {
+	// ID is empty for notifications.
+	ID     json.RawMessage `json:"id,omitempty"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params,omitempty"`
+}
+
+type lspResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *lspError       `json:"error,omitempty"`
+}
+
+type lspError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type lspNotification struct {
+	JSONRPC string `json:"jsonrpc"`
+	Method  string `json:"method"`
+	Params  any    `json:"params"`
+}
+
+// --- Protocol types ---
+
+type lspPosition struct {
+	Line      int `json:"line"`
+	Character int `json:"character"`
+}
+
+type lspRange struct {
+	Start lspPosition `json:"start"`
+	End   lspPosition `json:"end"`
+}
+
+type lspDiagnostic struct {
+	Range    lspRange `json:"range"`
+	Severity int      `json:"severity"`
+	Code     string   `json:"code"`
+	Source   string   `json:"source"`
+	Message  string   `json:"message"`
+}
+
+type lspTextEdit struct {
+	Range   lspRange `json:"range"`
+	NewText string   `json:"newText"`
+}
+
+type lspWorkspaceEdit struct {
+	Changes map[string][]lspTextEdit `json:"changes"`
+}
+
+type lspCommand struct {
+	Title     string `json:"title"`
+	Command   string `json:"command"`
+	Arguments []any  `json:"arguments,omitempty"`
+}
+
+type lspCodeAction struct {
+	Title       string            `json:"title"`
+	Kind        string            `json:"kind"`
+	Diagnostics []lspDiagnostic   `json:"diagnostics,omitempty"`
+	IsPreferred bool              `json:"isPreferred,omitempty"`
+	Edit        *lspWorkspaceEdit `json:"edit,omitempty"`
+	Command     *lspCommand       `json:"command,omitempty"`
+}
+
+type lspDocumentID struct {
+	URI string `json:"uri"`
+}
+
+type lspDidOpenParams struct {
+	TextDocument struct {
+		URI  string `json:"uri"`
+		Text string `json:"text"`
+	} `json:"textDocument"`
+}
+
+type lspDidChangeParams struct {
+	TextDocument   lspDocumentID `json:"textDocument"`
+	ContentChanges []struct {
+		Text string `json:"text"`
+	} `json:"contentChanges"`
+}
+
+type lspDidCloseParams struct {
+	TextDocument lspDocumentID `json:"textDocument"`
+}
+
+type lspCodeActionParams struct {
+	TextDocument lspDocumentID `json:"textDocument"`
+	Range        lspRange      `json:"range"`
+}
+
+type lspExecuteCommandParams struct {
+	Command   string            `json:"command"`
+	Arguments []json.RawMessage `json:"arguments"`
+}
+
+// lspInitializeResult announces the server's capabilities. Documents are synced in
+// full (1) on every change, and positions use the protocol's default UTF-16 offsets.
+var lspInitializeResult = map[string]any{
+	"capabilities": map[string]any{
+		"textDocumentSync":       map[string]any{"openClose": true, "change": 1},
+		"codeActionProvider":     map[string]any{"codeActionKinds": []string{"quickfix"}},
+		"executeCommandProvider": map[string]any{"commands": []string{lspAddToDictionary}},
+	},
+	"serverInfo": map[string]any{"name": "spellchecker"},
+}
+
+// --- Transport ---
+
+// run reads and handles messages until the client sends exit. It returns an error if
+// the client exits, or the input ends, without a shutdown request first.
+func (s *lspServer) run() error {
+	for {
+		body, err := s.read()
+		if err == io.EOF && s.shutdown {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		var req lspRequest
+		if err := json.Unmarshal(body, &req); err != nil {
+			if err := s.write(lspResponse{JSONRPC: "2.0", ID: json.RawMessage("null"), Error: &lspError{lspParseError, err.Error()}}); err != nil {
+				return err
+			}
+			continue
+		}
+		if req.Method == "exit" {
+			if !s.shutdown {
+				return errors.New("exit without shutdown")
+			}
+			return nil
+		}
+
+		result, rpcErr := s.handle(&req)
+		if len(req.ID) == 0 {
+			continue
+		}
+		resp := lspResponse{JSONRPC: "2.0", ID: req.ID, Error: rpcErr}
+		if rpcErr == nil {
+			if resp.Result, err = json.Marshal(result); err != nil {
+				return err
+			}
+		}
+		if err := s.write(resp); err != nil {
+			return err
+		}
+	}
+}
+
+// read returns the body of the next message, framed by a Content-Length header.
+func (s *lspServer) read() ([]byte, error) {
+	length := -1
+	for {
+		line, err := s.in.ReadString('\n')
+		if err != nil {
+			if err == io.EOF && line != "" {
+				err = io.ErrUnexpectedEOF
+			}
+			return nil, err
+		}
+		line = strings.TrimRight(line, "\r\n")
+		if line == "" {
+			break
+		}
+		name, value, ok := strings.Cut(line, ":")
+		if ok && strings.EqualFold(strings.TrimSpace(name), "Content-Length") {
+			if length, err = strconv.Atoi(strings.TrimSpace(value)); err != nil || length < 0 {
+				return nil, fmt.Errorf("invalid Content-Length %q", strings.TrimSpace(value))
+			}
+		}
+	}
+	if length < 0 {
+		return nil, errors.New("message without Content-Length header")
+	}
+	body := make([]byte, length)
+	if _, err := io.ReadFull(s.in, body); err != nil {
+		return nil, err
+	}
+	return body, nil
+}
+
+func (s *lspServer) write(message any) error {
+	body, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(s.out, "Content-Length: %d\r\n\r\n%s", len(body), body)
+	return err
+}
+
+// --- Methods ---
+
+// handle runs a request or a notification and returns the result of a request.
+// Unknown notifications are ignored.
+func (s *lspServer) handle(req *lspRequest) (any, *lspError) {
+	switch req.Method {
+	case "initialize":
+		return lspInitializeResult, nil
+	case "shutdown":
+		s.shutdown = true
+		return nil, nil
+	case "textDocument/didOpen":
+		var params lspDidOpenParams
+		if err := decodeParams(req, &params); err != nil {
+			return nil, err
+		}
+		return nil, s.check(params.TextDocument.URI, params.TextDocument.Text)
+	case "textDocument/didChange":
+		var params lspDidChangeParams
+		if err := decodeParams(req, &params); err != nil {
+			return nil, err
+		}
+		if n := len(params.ContentChanges); n > 0 {
+			// With full sync, the last change holds the whole new text.
+			return nil, s.check(params.TextDocument.URI, params.ContentChanges[n-1].Text)
+		}
+		return nil, nil
+	case "textDocument/didClose":
+		var params lspDidCloseParams
+		if err := decodeParams(req, &params); err != nil {
+			return nil, err
+		}
+		delete(s.documents, params.TextDocument.URI)
+		return nil, s.publish(params.TextDocument.URI, nil)
+	case "textDocument/codeAction":
+		var params lspCodeActionParams
+		if err := decodeParams(req, &params); err != nil {
+			return nil, err
+		}
+		return s.codeActions(params), nil
+	case "workspace/executeCommand":
+		var params lspExecuteCommandParams
+		if err := decodeParams(req, &params); err != nil {
+			return nil, err
+		}
+		return nil, s.executeCommand(params)
+	}
+	if len(req.ID) == 0 {
+		return nil, nil
+	}
+	return nil, &lspError{lspMethodNotFound, "method not found: " + req.Method}
+}
+
+func decodeParams(req *lspRequest, params any) *lspError {
+	if err := json.Unmarshal(req.Params, params); err != nil {
+		return &lspError{lspInvalidParams, fmt.Sprintf("invalid params for %s: %v", req.Method, err)}
+	}
+	return nil
+}
+
+// check checks the text of a document and publishes its diagnostics.
+func (s *lspServer) check(uri, text string) *lspError {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSuffix(line, "\r")
+	}
+	path := uriPath(uri)
+	doc := &lspDocument{
+		path:  path,
+		lines: lines,
+		// checkLines blanks what it does not check, so it gets its own copy.
+		typos: checkLines(path, append([]string(nil), lines...), s.dictionary, s.opts),
+	}
+	s.documents[uri] = doc
+	return s.publish(uri, doc)
+}
+
+// publish sends the diagnostics of a document; a nil document clears them.
+func (s *lspServer) publish(uri string, doc *lspDocument) *lspError {
+	diagnostics := []lspDiagnostic{}
+	if doc != nil {
+		for _, typo := range doc.typos {
+			diagnostics = append(diagnostics, doc.diagnostic(typo))
+		}
+	}
+	err := s.write(lspNotification{
+		JSONRPC: "2.0",
+		Method:  "textDocument/publishDiagnostics",
+		Params:  map[string]any{"uri": uri, "diagnostics": diagnostics},
+	})
+	if err != nil {
+		return &lspError{lspInternalError, err.Error()}
+	}
+	return nil
+}
+
+// codeActions offers, for each typo in the requested range, a quick fix per suggestion
+// and, when a personal dictionary is configured, one adding the word to it.
+func (s *lspServer) codeActions(params lspCodeActionParams) []lspCodeAction {
+	actions := []lspCodeAction{}
+	uri := params.TextDocument.URI
+	doc := s.documents[uri]
+	if doc == nil {
+		return actions
+	}
+	for _, typo := range doc.typos {
+		diagnostic := doc.diagnostic(typo)
+		if !rangesOverlap(diagnostic.Range, params.Range) || typo.rule() == RuleUnusedDirective {
+			continue
+		}
+		preferred, confident := confidentSuggestion(typo, s.dictionary, s.opts.Suggestions)
+		for _, suggestion := range typo.Suggestions {
+			replacement := MatchCase(typo.Word, suggestion)
+			actions = append(actions, lspCodeAction{
+				Title:       fmt.Sprintf("Replace with \"%s\"", replacement),
+				Kind:        "quickfix",
+				Diagnostics: []lspDiagnostic{diagnostic},
+				IsPreferred: confident && suggestion == preferred,
+				Edit: &lspWorkspaceEdit{Changes: map[string][]lspTextEdit{
+					uri: {{Range: diagnostic.Range, NewText: replacement}},
+				}},
+			})
+		}
+		if s.personal != "" {
+			title := fmt.Sprintf("Add \"%s\" to personal dictionary", typo.Word)
+			actions = append(actions, lspCodeAction{
+				Title:       title,
+				Kind:        "quickfix",
+				Diagnostics: []lspDiagnostic{diagnostic},
+				Command:     &lspCommand{Title: title, Command: lspAddToDictionary, Arguments: []any{typo.Word}},
+			})
+		}
+	}
+	return actions
+}
+
+// executeCommand adds a word to the personal dictionary, then checks the open
+// documents again.
+func (s *lspServer) executeCommand(params lspExecuteCommandParams) *lspError {
+	if params.Command != lspAddToDictionary {
+		return &lspError{lspInvalidParams, "unknown command: " + params.Command}
+	}
+	var word string
+	if len(params.Arguments) != 1 || json.Unmarshal(params.Arguments[0], &word) != nil || word == "" {
+		return &lspError{lspInvalidParams, lspAddToDictionary + " takes one word"}
+	}
+	if s.personal == "" {
+		return &lspError{lspInvalidParams, "no personal dictionary is configured"}
+	}
+	if err := AppendPersonalWords(s.personal, []string{word}); err != nil {
+		return &lspError{lspInternalError, fmt.Sprintf("adding %q to %s: %v", word, s.personal, err)}
+	}
+	key := normalizeWord(word)
+	_, known := s.dictionary.Words[key]
+	s.dictionary.Words[key] = struct{}{}
+	delete(s.dictionary.Forbidden, key)
+	if s.dictionary.index != nil {
+		s.dictionary.index.insert(key)
+	}
+	if s.dictionary.phonetic != nil && !known {
+		s.dictionary.phonetic.add(key)
+	}
+
+	uris := make([]string, 0, len(s.documents))
+	for uri := range s.documents {
+		uris = append(uris, uri)
+	}
+	sort.Strings(uris)
+	for _, uri := range uris {
+		if err := s.check(uri, strings.Join(s.documents[uri].lines, "\n")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// --- Positions ---
+
+// diagnostic describes a typo of the document for the editor.
+func (doc *lspDocument) diagnostic(typo MisspelledWord) lspDiagnostic {
+	described := typo
+	described.Suggestions = nil
+	return lspDiagnostic{
+		Range:    doc.wordRange(typo),
+		Severity: lspSeverities[typo.rule()],
+		Code:     typo.rule(),
+		Source:   "spellchecker",
+		Message:  typoMessage(described),
+	}
+}
+
+// wordRange converts the line and rune column of a typo into an LSP range, whose
+// characters are counted in UTF-16 code units.
+func (doc *lspDocument) wordRange(typo MisspelledWord) lspRange {
+	line := typo.LineNumber - 1
+	var text string
+	if line >= 0 && line < len(doc.lines) {
+		text = doc.lines[line]
+	}
+	start := utf16Length([]rune(text), typo.Column-1)
+	end := start + utf16Length([]rune(typo.Word), -1)
+	return lspRange{Start: lspPosition{line, start}, End: lspPosition{line, end}}
+}
+
+// utf16Length returns the number of UTF-16 code units of the first n runes, or of all
+// of them if n is negative or too large.
+func utf16Length(runes []rune, n int) int {
+	if n < 0 || n > len(runes) {
+		n = len(runes)
+	}
+	length := 0
+	for _, r := range runes[:n] {
+		if utf16.RuneLen(r) == 2 {
+			length += 2
+		} else {
+			length++
+		}
+	}
+	return length
+}
+
+func rangesOverlap(a, b lspRange) bool {
+	return !positionBefore(a.End, b.Start) && !positionBefore(b.End, a.Start)
+}
+
+func positionBefore(a, b lspPosition) bool {
+	return a.Line < b.Line || (a.Line == b.Line && a.Character < b.Character)
+}
+
+// uriPath returns the file path of a file URI. Other URIs are returned as they are;
+// the path only selects how the document is checked, by its extension or name.
+func uriPath(uri string) string {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != "file" {
+		return uri
+	}
+	path := u.Path
+	if len(path) > 2 && path[0] == '/' && path[2] == ':' {
+		// A Windows drive letter, as in file:///C:/src/main.go.
+		path = path[1:]
+	}
+	return filepath.FromSlash(path)
+}
@@ -0,0 +1,238 @@
+package spellchecker
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// lspScript frames client messages the way an editor sends them over stdio.
+type lspScript struct {
+	buf    bytes.Buffer
+	nextID int
+}
+
+func (c *lspScript) request(method string, params any) int {
+	c.nextID++
+	c.send(map[string]any{"jsonrpc": "2.0", "id": c.nextID, "method": method, "params": params})
+	return c.nextID
+}
+
+func (c *lspScript) notify(method string, params any) {
+	c.send(map[string]any{"jsonrpc": "2.0", "method": method, "params": params})
+}
+
+func (c *lspScript) send(message any) {
+	body, _ := json.Marshal(message)
+	fmt.Fprintf(&c.buf, "Content-Length: %d\r\n\r\n%s", len(body), body)
+}
+
+// lspMessage is a message of the server, a response or a notification.
+type lspMessage struct {
+	ID     int             `json:"id"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+	Result json.RawMessage `json:"result"`
+	Error  *lspError       `json:"error"`
+}
+
+type lspPublished struct {
+	URI         string          `json:"uri"`
+	Diagnostics []lspDiagnostic `json:"diagnostics"`
+}
+
+func readLSPMessages(t *testing.T, out []byte) []lspMessage {
+	t.Helper()
+	var messages []lspMessage
+	r := bufio.NewReader(bytes.NewReader(out))
+	for {
+		s := &lspServer{in: r}
+		body, err := s.read()
+		if err == io.EOF {
+			return messages
+		}
+		if err != nil {
+			t.Fatalf("reading server output: %v", err)
+		}
+		var m lspMessage
+		if err := json.Unmarshal(body, &m); err != nil {
+			t.Fatalf("invalid server message %s: %v", body, err)
+		}
+		messages = append(messages, m)
+	}
+}
+
+func TestLSPSession(t *testing.T) {
+	dir := t.TempDir()
+	personal := filepath.Join(dir, "words.txt")
+	dictionary := &Dictionary{
+		Words:     map[string]struct{}{"hello": {}, "world": {}, "the": {}, "cat": {}, "rocket": {}},
+		Forbidden: map[string]struct{}{},
+	}
+	dictionary.BuildPhoneticIndex()
+	opts := CheckOptions{Suggestions: SuggestionOptions{Max: 5}}
+	uri := "file://" + filepath.ToSlash(filepath.Join(dir, "notes.txt"))
+
+	var c lspScript
+	c.request("initialize", map[string]any{"capabilities": map[string]any{}})
+	c.notify("initialized", map[string]any{})
+	// The emoji takes two UTF-16 code units, which diagnostics must count.
+	c.notify("textDocument/didOpen", map[string]any{"textDocument": map[string]any{
+		"uri": uri, "languageId": "plaintext", "version": 1, "text": "hello wrld\r\n🚀 Teh cat",
+	}})
+	actions := c.request("textDocument/codeAction", map[string]any{
+		"textDocument": map[string]any{"uri": uri},
+		"range":        map[string]any{"start": map[string]any{"line": 1, "character": 4}, "end": map[string]any{"line": 1, "character": 4}},
+		"context":      map[string]any{"diagnostics": []any{}},
+	})
+	c.notify("textDocument/didChange", map[string]any{
+		"textDocument":   map[string]any{"uri": uri, "version": 2},
+		"contentChanges": []any{map[string]any{"text": "hello wrld qwzx"}},
+	})
+	c.request("workspace/executeCommand", map[string]any{"command": lspAddToDictionary, "arguments": []any{"qwzx"}})
+	unknown := c.request("textDocument/hover", map[string]any{})
+	c.notify("textDocument/didClose", map[string]any{"textDocument": map[string]any{"uri": uri}})
+	shutdown := c.request("shutdown", nil)
+	c.notify("exit", nil)
+
+	var out bytes.Buffer
+	if err := runLSP(&c.buf, &out, dictionary, opts, personal); err != nil {
+		t.Fatalf("runLSP() error = %v", err)
+	}
+	messages := readLSPMessages(t, out.Bytes())
+
+	var published [][]lspDiagnostic
+	responses := make(map[int]lspMessage)
+	for _, m := range messages {
+		if m.Method == "textDocument/publishDiagnostics" {
+			var p lspPublished
+			json.Unmarshal(m.Params, &p)
+			if p.URI != uri {
+				t.Errorf("diagnostics published for %q, want %q", p.URI, uri)
+			}
+			published = append(published, p.Diagnostics)
+			continue
+		}
+		responses[m.ID] = m
+	}
+
+	if len(published) != 4 {
+		t.Fatalf("got %d diagnostics notifications, want 4 (open, change, add, close)", len(published))
+	}
+	wantOpen := []lspDiagnostic{
+		{Range: lspRange{lspPosition{0, 6}, lspPosition{0, 10}}, Severity: 2, Code: RuleUnknownWord, Source: "spellchecker", Message: `"wrld" appears to be a typo.`},
+		{Range: lspRange{lspPosition{1, 3}, lspPosition{1, 6}}, Severity: 2, Code: RuleUnknownWord, Source: "spellchecker", Message: `"Teh" appears to be a typo.`},
+	}
+	if !reflect.DeepEqual(published[0], wantOpen) {
+		t.Errorf("diagnostics on open = %+v, want %+v", published[0], wantOpen)
+	}
+	if len(published[1]) != 2 || published[1][1].Range.Start.Character != 11 {
+		t.Errorf("diagnostics on change = %+v, want wrld and qwzx", published[1])
+	}
+	if len(published[2]) != 1 || !strings.Contains(published[2][0].Message, "wrld") {
+		t.Errorf("diagnostics after adding qwzx = %+v, want only wrld", published[2])
+	}
+	if len(published[3]) != 0 {
+		t.Errorf("diagnostics on close = %+v, want none", published[3])
+	}
+
+	var gotActions []lspCodeAction
+	json.Unmarshal(responses[actions].Result, &gotActions)
+	if len(gotActions) != 2 {
+		t.Fatalf("got %d code actions, want 2: %+v", len(gotActions), gotActions)
+	}
+	replace := gotActions[0]
+	if replace.Title != `Replace with "The"` || !replace.IsPreferred {
+		t.Errorf("first action = %q (preferred %v), want a preferred replacement with \"The\"", replace.Title, replace.IsPreferred)
+	}
+	wantEdit := []lspTextEdit{{Range: lspRange{lspPosition{1, 3}, lspPosition{1, 6}}, NewText: "The"}}
+	if replace.Edit == nil || !reflect.DeepEqual(replace.Edit.Changes[uri], wantEdit) {
+		t.Errorf("replacement edit = %+v, want %+v", replace.Edit, wantEdit)
+	}
+	if add := gotActions[1]; add.Command == nil || add.Command.Command != lspAddToDictionary || !reflect.DeepEqual(add.Command.Arguments, []any{"Teh"}) {
+		t.Errorf("second action = %+v, want adding Teh to the dictionary", add)
+	}
+
+	if words, _ := os.ReadFile(personal); string(words) != "qwzx\n" {
+		t.Errorf("personal dictionary = %q, want %q", words, "qwzx\n")
+	}
+	// The added word can be suggested as a sound-alike too.
+	if !slices.Contains(dictionary.phonetic.lookup("qwzx"), "qwzx") {
+		t.Error("the added word is missing from the phonetic index")
+	}
+	if m := responses[unknown]; m.Error == nil || m.Error.Code != lspMethodNotFound {
+		t.Errorf("unknown method response = %+v, want a method not found error", m)
+	}
+	if m := responses[shutdown]; m.Error != nil || string(m.Result) != "null" {
+		t.Errorf("shutdown response = %+v, want a null result", m)
+	}
+}
+
+func TestLSPExitWithoutShutdown(t *testing.T) {
+	var c lspScript
+	c.notify("exit", nil)
+	if err := runLSP(&c.buf, io.Discard, &Dictionary{}, CheckOptions{}, ""); err == nil {
+		t.Error("runLSP() error = nil, want an error for exit without shutdown")
+	}
+}
+
+func TestLSPInvalidMessage(t *testing.T) {
+	var c lspScript
+	c.buf.WriteString("Content-Length: 5\r\n\r\n{nope")
+	c.request("shutdown", nil)
+	c.notify("exit", nil)
+	var out bytes.Buffer
+	if err := runLSP(&c.buf, &out, &Dictionary{}, CheckOptions{}, ""); err != nil {
+		t.Fatalf("runLSP() error = %v", err)
+	}
+	messages := readLSPMessages(t, out.Bytes())
+	if len(messages) != 2 || messages[0].Error == nil || messages[0].Error.Code != lspParseError {
+		t.Errorf("responses = %+v, want a parse error, then the shutdown result", messages)
+	}
+}
+
+func TestLSPAddToDictionaryWriteError(t *testing.T) {
+	// The personal dictionary cannot be created in a directory that does not exist.
+	personal := filepath.Join(t.TempDir(), "missing", "words.txt")
+	var c lspScript
+	add := c.request("workspace/executeCommand", map[string]any{"command": lspAddToDictionary, "arguments": []any{"qwzx"}})
+	c.request("shutdown", nil)
+	c.notify("exit", nil)
+	var out bytes.Buffer
+	if err := runLSP(&c.buf, &out, &Dictionary{Words: map[string]struct{}{}}, CheckOptions{}, personal); err != nil {
+		t.Fatalf("runLSP() error = %v", err)
+	}
+	messages := readLSPMessages(t, out.Bytes())
+	if len(messages) != 2 || messages[0].ID != add || messages[0].Error == nil || messages[0].Error.Code != lspInternalError {
+		t.Errorf("responses = %+v, want an internal error, then the shutdown result", messages)
+	}
+}
+
+func TestUTF16Length(t *testing.T) {
+	runes := []rune("a🚀é")
+	for n, want := range map[int]int{0: 0, 1: 1, 2: 3, 3: 4, -1: 4, 9: 4} {
+		if got := utf16Length(runes, n); got != want {
+			t.Errorf("utf16Length(%q, %d) = %d, want %d", string(runes), n, got, want)
+		}
+	}
+}
+
+func TestURIPath(t *testing.T) {
+	testCases := map[string]string{
+		"file:///home/me/notes%20v2.md": filepath.FromSlash("/home/me/notes v2.md"),
+		"untitled:Untitled-1":           "untitled:Untitled-1",
+	}
+	for uri, want := range testCases {
+		if got := uriPath(uri); got != want {
+			t.Errorf("uriPath(%q) = %q, want %q", uri, got, want)
+		}
+	}
+}
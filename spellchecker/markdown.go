@@ -0,0 +1,303 @@
+package spellchecker
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+var (
+	// fenceRegex matches the opening or closing line of a fenced code block.
+	fenceRegex = regexp.MustCompile("^ {0,3}(`{3,}|~{3,})")
+	// indentedCodeRegex matches a line indented by four columns, which is part of an
+	// indented code block unless it continues a paragraph or a list item.
+	indentedCodeRegex = regexp.MustCompile(`^(?: {4}| {0,3}\t)`)
+	// listItemRegex matches the first line of a list item.
+	listItemRegex = regexp.MustCompile(`^ {0,3}(?:[-+*]|[0-9]{1,9}[.)])(?:[ \t]|$)`)
+	// headingRegex matches an ATX heading, which an indented code block may follow
+	// directly, unlike a paragraph.
+	headingRegex = regexp.MustCompile(`^ {0,3}#{1,6}(?:[ \t]|$)`)
+	// refDefinitionRegex matches a link reference definition such as `[id]: https://example.com "Title"`.
+	// The optional title is prose and is kept.
+	refDefinitionRegex = regexp.MustCompile(`^ {0,3}\[[^\]]+\]:\s*\S+(?:\s+(?:"([^"]*)"|'([^']*)'|\(([^)]*)\)))?\s*$`)
+	// htmlTagRegex matches opening, closing and self-closing HTML tags, including their attributes.
+	htmlTagRegex = regexp.MustCompile(`</?[A-Za-z][A-Za-z0-9-]*(?:\s+[^<>]*)?/?>`)
+	// autolinkRegex matches autolinks such as <https://example.com> and <user@example.com>.
+	autolinkRegex = regexp.MustCompile(`<(?:[A-Za-z][A-Za-z0-9+.-]{1,31}:[^\s<>]*|[^\s<>@]+@[^\s<>@]+)>`)
+	// bareURLRegex matches URLs and e-mail addresses written directly in the text.
+	bareURLRegex = regexp.MustCompile(`(?:[A-Za-z][A-Za-z0-9+.-]*://|www\.)[^\s<>()\[\]]+|[\w.+-]+@[\w-]+(?:\.[\w-]+)+`)
+	// entityRegex matches HTML character references such as &nbsp; and &#8212;.
+	entityRegex = regexp.MustCompile(`&(?:[A-Za-z][A-Za-z0-9]*|#[0-9]+|#[xX][0-9A-Fa-f]+);`)
+)
+
+// extractMarkdownProse keeps the prose of a Markdown document: headings, paragraphs,
+// list items, block quotes, link text and image alt text. Front matter, fenced and
+// indented code blocks, inline code, link and image destinations, reference
+// definitions, HTML tags and comments, URLs and character references are blanked out.
+func extractMarkdownProse(lines []string) []string {
+	return extractMarkdown(lines, false)
+}
+
+// extractMarkdownComments keeps only the HTML comments of a Markdown document, outside
+// front matter and code blocks.
+func extractMarkdownComments(lines []string) []string {
+	return extractMarkdown(lines, true)
+}
+
+// extractMarkdown keeps the prose of a Markdown document, or only its HTML comments.
+func extractMarkdown(lines []string, comments bool) []string {
+	out := make([]string, len(lines))
+	var fence string
+	var inline inlineState
+	// paragraph reports whether the previous line was paragraph text, which a line
+	// indented by four columns continues instead of opening a code block; inList
+	// whether such a line may belong to a list item instead. afterBlank reports
+	// whether the previous line was blank.
+	paragraph, inList, afterBlank := false, false, false
+	// Without a closing delimiter, a first "---" line is a thematic break.
+	frontMatter := len(lines) > 0 && strings.TrimRight(lines[0], " \t") == "---" &&
+		slices.ContainsFunc(lines[1:], isFrontMatterEnd)
+
+	for i, line := range lines {
+		switch {
+		case frontMatter:
+			out[i] = blank(line)
+			if i > 0 && isFrontMatterEnd(line) {
+				frontMatter = false
+			}
+		case fence != "":
+			out[i] = blank(line)
+			if m := fenceRegex.FindStringSubmatch(line); m != nil && m[1][0] == fence[0] && len(m[1]) >= len(fence) &&
+				strings.TrimSpace(line[len(m[0]):]) == "" {
+				fence = ""
+			}
+		default:
+			if m := fenceRegex.FindStringSubmatch(line); m != nil {
+				fence = m[1]
+				out[i] = blank(line)
+				paragraph, afterBlank = false, false
+				continue
+			}
+			if strings.TrimSpace(line) == "" {
+				out[i] = line
+				paragraph, afterBlank, inline.codeSpan = false, true, 0
+				continue
+			}
+			if listItemRegex.MatchString(line) {
+				inList = true
+			} else if afterBlank && line[0] != ' ' && line[0] != '\t' {
+				inList = false
+			}
+			afterBlank = false
+			if !paragraph && !inList && !inline.comment && indentedCodeRegex.MatchString(line) {
+				out[i] = blank(line)
+				continue
+			}
+			paragraph = !headingRegex.MatchString(line)
+			runes := []rune(line)
+			if comments {
+				inComment := maskInline([]rune(line), &inline, lines[i+1:])
+				for j := range runes {
+					if !inComment[j] {
+						runes[j] = ' '
+					}
+				}
+				out[i] = string(runes)
+				continue
+			}
+			maskInline(runes, &inline, lines[i+1:])
+			maskReferenceDefinition(runes)
+			maskRegex(runes, autolinkRegex)
+			maskRegex(runes, htmlTagRegex)
+			maskLinkDestinations(runes)
+			maskRegex(runes, bareURLRegex)
+			maskRegex(runes, entityRegex)
+			out[i] = string(runes)
+		}
+	}
+	return out
+}
+
+// isFrontMatterEnd reports whether line closes a front matter block.
+func isFrontMatterEnd(line string) bool {
+	trimmed := strings.TrimRight(line, " \t")
+	return trimmed == "---" || trimmed == "..."
+}
+
+// blank replaces every rune of s with a space.
+func blank(s string) string {
+	return strings.Repeat(" ", utf8.RuneCountInString(s))
+}
+
+// maskRunes replaces runes[start:end] with spaces.
+func maskRunes(runes []rune, start, end int) {
+	for i := start; i < end && i < len(runes); i++ {
+		runes[i] = ' '
+	}
+}
+
+// maskRegex blanks every match of re in the line.
+func maskRegex(runes []rune, re *regexp.Regexp) {
+	line := string(runes)
+	for _, m := range re.FindAllStringIndex(line, -1) {
+		start := utf8.RuneCountInString(line[:m[0]])
+		maskRunes(runes, start, start+utf8.RuneCountInString(line[m[0]:m[1]]))
+	}
+}
+
+// inlineState carries an HTML comment or a code span over from one line to the next.
+type inlineState struct {
+	// comment reports whether the line starts inside an HTML comment.
+	comment bool
+	// codeSpan is the length of the backtick run of a code span that continues from
+	// the previous line, or 0.
+	codeSpan int
+}
+
+// maskInline blanks the HTML comments and inline code spans of a line, both of which
+// may span several lines, and reports which runes were part of a comment. Whichever
+// opens first wins, so a comment written in code, as in `<!-- x -->`, is code. A span
+// opened by a run of N backticks is closed by the next run of exactly N backticks,
+// which may be on one of the next lines of the same paragraph; an unmatched run is
+// literal text.
+func maskInline(runes []rune, state *inlineState, next []string) []bool {
+	inComment := make([]bool, len(runes))
+	i := 0
+	if state.codeSpan > 0 {
+		closeStart := closingBacktickRun(runes, 0, state.codeSpan)
+		if closeStart < 0 {
+			maskRunes(runes, 0, len(runes))
+			return inComment
+		}
+		maskRunes(runes, 0, closeStart+state.codeSpan)
+		i, state.codeSpan = closeStart+state.codeSpan, 0
+	}
+	for i < len(runes) {
+		switch {
+		case state.comment:
+			end := i
+			for end < len(runes) && !hasPrefixAt(runes, end, "-->") {
+				end++
+			}
+			if end < len(runes) {
+				end += len("-->")
+				state.comment = false
+			}
+			for j := i; j < end; j++ {
+				inComment[j] = true
+			}
+			maskRunes(runes, i, end)
+			i = end
+		case hasPrefixAt(runes, i, "<!--"):
+			state.comment = true
+		case runes[i] == '`':
+			n := backtickRun(runes, i)
+			closeStart := closingBacktickRun(runes, i+n, n)
+			switch {
+			case closeStart >= 0:
+				maskRunes(runes, i, closeStart+n)
+				i = closeStart + n
+			case codeSpanContinues(next, n):
+				maskRunes(runes, i, len(runes))
+				state.codeSpan = n
+				return inComment
+			default:
+				i += n
+			}
+		default:
+			i++
+		}
+	}
+	return inComment
+}
+
+// maskReferenceDefinition blanks a link reference definition, keeping only its title.
+func maskReferenceDefinition(runes []rune) {
+	line := string(runes)
+	m := refDefinitionRegex.FindStringSubmatchIndex(line)
+	if m == nil {
+		return
+	}
+	titleStart, titleEnd := len(line), len(line)
+	for g := 1; g <= 3; g++ {
+		if m[2*g] >= 0 {
+			titleStart, titleEnd = m[2*g], m[2*g+1]
+		}
+	}
+	maskRunes(runes, 0, utf8.RuneCountInString(line[:titleStart]))
+	maskRunes(runes, utf8.RuneCountInString(line[:titleEnd]), len(runes))
+}
+
+// closingBacktickRun returns the index of the first run of exactly n backticks at or
+// after runes[from], or -1 if there is none.
+func closingBacktickRun(runes []rune, from, n int) int {
+	for j := from; j < len(runes); {
+		if runes[j] != '`' {
+			j++
+			continue
+		}
+		m := backtickRun(runes, j)
+		if m == n {
+			return j
+		}
+		j += m
+	}
+	return -1
+}
+
+// codeSpanContinues reports whether a code span opened by a run of n backticks is
+// closed on one of lines before its paragraph ends at a blank line or a fence.
+func codeSpanContinues(lines []string, n int) bool {
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" || fenceRegex.MatchString(line) {
+			return false
+		}
+		if closingBacktickRun([]rune(line), 0, n) >= 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// backtickRun returns the number of consecutive backticks starting at runes[i].
+func backtickRun(runes []rune, i int) int {
+	n := 0
+	for i+n < len(runes) && runes[i+n] == '`' {
+		n++
+	}
+	return n
+}
+
+// maskLinkDestinations blanks the destination of inline links and images, as in
+// [text](url "title") and ![alt](path), and the label of reference links, as in
+// [text][label]. The link text and alt text are kept.
+func maskLinkDestinations(runes []rune) {
+	for i := 0; i+1 < len(runes); i++ {
+		if runes[i] != ']' {
+			continue
+		}
+		var opening, closing rune
+		switch runes[i+1] {
+		case '(':
+			opening, closing = '(', ')'
+		case '[':
+			opening, closing = '[', ']'
+		default:
+			continue
+		}
+		depth := 0
+		for j := i + 1; j < len(runes); j++ {
+			if runes[j] == opening {
+				depth++
+			} else if runes[j] == closing {
+				depth--
+				if depth == 0 {
+					maskRunes(runes, i+1, j+1)
+					i = j
+					break
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package spellchecker
+
+import (
+	"reflect"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestExtractMarkdownProse(t *testing.T) {
+	testCases := []struct {
+		name  string
+		lines []string
+		want  []string
+	}{
+		{
+			name:  "plain prose is unchanged",
+			lines: []string{"# A heading", "- a list item", "> a quote"},
+			want:  []string{"# A heading", "- a list item", "> a quote"},
+		},
+		{
+			name:  "fenced code blocks are blanked",
+			lines: []string{"text", "```go", "func mian() {}", "```", "more"},
+			want:  []string{"text", "     ", "              ", "   ", "more"},
+		},
+		{
+			name:  "longer fence is not closed by a shorter one",
+			lines: []string{"~~~~", "~~~", "code", "~~~~", "prose"},
+			want:  []string{"    ", "   ", "    ", "    ", "prose"},
+		},
+		{
+			name:  "inline code is blanked",
+			lines: []string{"call `parseDictinary` now, or ``a ` b``"},
+			want:  []string{"call                  now, or          "},
+		},
+		{
+			name:  "link and image destinations are blanked but text is kept",
+			lines: []string{"see [the docs](https://exmple.com/pth) and ![a logo](img/logoo.png)"},
+			want:  []string{"see [the docs]                         and ![a logo]               "},
+		},
+		{
+			name:  "reference links keep their text only",
+			lines: []string{"read [the guide][gide]", `[gide]: https://exmple.com "Guide title"`},
+			want:  []string{"read [the guide]      ", `                            Guide title `},
+		},
+		{
+			name:  "HTML tags, comments and entities are blanked",
+			lines: []string{`<div class="notte">Hello&nbsp;world</div> <!-- tdo`, "still hiden -->after"},
+			want:  []string{`                   Hello      world               `, "               after"},
+		},
+		{
+			name:  "bare URLs, autolinks and e-mail addresses are blanked",
+			lines: []string{"visit www.exmple.com or <https://exmple.org> or mail adm@exmple.net"},
+			want:  []string{"visit                or                      or mail               "},
+		},
+		{
+			name:  "front matter is blanked",
+			lines: []string{"---", "titel: x", "---", "Body"},
+			want:  []string{"   ", "        ", "   ", "Body"},
+		},
+		{
+			name:  "an unclosed front matter is a thematic break",
+			lines: []string{"---", "Some prose with a tpyo."},
+			want:  []string{"---", "Some prose with a tpyo."},
+		},
+		{
+			name:  "indented code blocks are blanked",
+			lines: []string{"# Usage", "    go run mian.go", "", "Run it:", "", "\tfmt.Prinln()", "", "    more cde", "after"},
+			want:  []string{"# Usage", "                  ", "", "Run it:", "", "             ", "", "            ", "after"},
+		},
+		{
+			name:  "indented lines continue paragraphs and list items",
+			lines: []string{"Some text", "    continued hre", "", "- item", "", "    still the itme", "", "Back to prose", "", "    now cde"},
+			want:  []string{"Some text", "    continued hre", "", "- item", "", "    still the itme", "", "Back to prose", "", "           "},
+		},
+		{
+			name:  "inline code may span lines of a paragraph",
+			lines: []string{"call `parse", "Dictinary()` now", "an unclosed ` tick", "", "` is literal"},
+			want:  []string{"call       ", "             now", "an unclosed ` tick", "", "` is literal"},
+		},
+		{
+			name:  "a comment opener in inline code is code",
+			lines: []string{"type `<!--` then tpyo", "and a `tick` <!-- in `a` comment -->"},
+			want:  []string{"type        then tpyo", "and a                               "},
+		},
+		{
+			name:  "columns are preserved after multi-byte characters",
+			lines: []string{"café `cde` naïve"},
+			want:  []string{"café       naïve"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := extractMarkdownProse(tc.lines)
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("extractMarkdownProse() mismatch.\nGOT:\n%q\nWANT:\n%q", got, tc.want)
+			}
+			for i := range got {
+				if utf8.RuneCountInString(got[i]) != utf8.RuneCountInString(tc.lines[i]) {
+					t.Errorf("line %d changed length: %q -> %q", i+1, tc.lines[i], got[i])
+				}
+			}
+		})
+	}
+}
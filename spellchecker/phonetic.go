@@ -0,0 +1,664 @@
+package spellchecker
+
+import (
+	"strings"
+)
+
+// metaphoneMaxLength is the length of Double Metaphone keys, as in the original algorithm.
+const metaphoneMaxLength = 4
+
+// doubleMetaphone returns the primary and alternate Double Metaphone keys of a word
+// (Lawrence Philips, 2000). Words that sound alike share a key even when they are
+// spelled very differently: "fonetik" and "phonetic" are both "FNTK", "nite" and
+// "night" are both "NT".
+func doubleMetaphone(word string) (string, string) {
+	m := &metaphone{value: []rune(strings.ToUpper(word))}
+	m.slavoGermanic = m.isSlavoGermanic()
+	if len(m.value) == 0 {
+		return "", ""
+	}
+
+	index := 0
+	if m.contains(0, 2, "GN", "KN", "PN", "WR", "PS") {
+		index = 1
+	}
+	if m.charAt(0) == 'X' {
+		m.add("S", "S")
+		index = 1
+	}
+
+	for !m.full() && index < len(m.value) {
+		switch c := m.charAt(index); c {
+		case 'A', 'E', 'I', 'O', 'U', 'Y':
+			if index == 0 {
+				m.add("A", "A")
+			}
+			index++
+		case 'B':
+			m.add("P", "P")
+			index = m.skipDouble(index, 'B')
+		case 'Ç':
+			m.add("S", "S")
+			index++
+		case 'C':
+			index = m.handleC(index)
+		case 'D':
+			index = m.handleD(index)
+		case 'F':
+			m.add("F", "F")
+			index = m.skipDouble(index, 'F')
+		case 'G':
+			index = m.handleG(index)
+		case 'H':
+			index = m.handleH(index)
+		case 'J':
+			index = m.handleJ(index)
+		case 'K':
+			m.add("K", "K")
+			index = m.skipDouble(index, 'K')
+		case 'L':
+			index = m.handleL(index)
+		case 'M':
+			m.add("M", "M")
+			if m.conditionM0(index) {
+				index += 2
+			} else {
+				index++
+			}
+		case 'N':
+			m.add("N", "N")
+			index = m.skipDouble(index, 'N')
+		case 'Ñ':
+			m.add("N", "N")
+			index++
+		case 'P':
+			index = m.handleP(index)
+		case 'Q':
+			m.add("K", "K")
+			index = m.skipDouble(index, 'Q')
+		case 'R':
+			index = m.handleR(index)
+		case 'S':
+			index = m.handleS(index)
+		case 'T':
+			index = m.handleT(index)
+		case 'V':
+			m.add("F", "F")
+			index = m.skipDouble(index, 'V')
+		case 'W':
+			index = m.handleW(index)
+		case 'X':
+			index = m.handleX(index)
+		case 'Z':
+			index = m.handleZ(index)
+		default:
+			index++
+		}
+	}
+	return m.primary.String(), m.alternate.String()
+}
+
+// metaphone holds the state of one Double Metaphone encoding.
+type metaphone struct {
+	value              []rune
+	slavoGermanic      bool
+	primary, alternate strings.Builder
+}
+
+func (m *metaphone) isSlavoGermanic() bool {
+	s := string(m.value)
+	return strings.ContainsAny(s, "WK") || strings.Contains(s, "CZ") || strings.Contains(s, "WITZ")
+}
+
+// full reports whether both keys have reached their maximum length.
+func (m *metaphone) full() bool {
+	return m.primary.Len() >= metaphoneMaxLength && m.alternate.Len() >= metaphoneMaxLength
+}
+
+// add appends to both keys, up to the maximum key length.
+func (m *metaphone) add(primary, alternate string) {
+	m.addPrimary(primary)
+	m.addAlternate(alternate)
+}
+
+func (m *metaphone) addPrimary(s string) {
+	appendKey(&m.primary, s)
+}
+
+func (m *metaphone) addAlternate(s string) {
+	appendKey(&m.alternate, s)
+}
+
+// appendKey appends s to key, truncated to the maximum key length. Keys are ASCII.
+func appendKey(key *strings.Builder, s string) {
+	room := metaphoneMaxLength - key.Len()
+	if room <= 0 {
+		return
+	}
+	if len(s) > room {
+		s = s[:room]
+	}
+	key.WriteString(s)
+}
+
+// charAt returns the rune at index, or 0 outside the word.
+func (m *metaphone) charAt(index int) rune {
+	if index < 0 || index >= len(m.value) {
+		return 0
+	}
+	return m.value[index]
+}
+
+// contains reports whether the length runes starting at start equal one of criteria.
+func (m *metaphone) contains(start, length int, criteria ...string) bool {
+	if start < 0 || start+length > len(m.value) {
+		return false
+	}
+	target := string(m.value[start : start+length])
+	for _, c := range criteria {
+		if target == c {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *metaphone) isVowel(index int) bool {
+	switch m.charAt(index) {
+	case 'A', 'E', 'I', 'O', 'U', 'Y':
+		return true
+	}
+	return false
+}
+
+// skipDouble moves past the letter at index and a repetition of c after it.
+func (m *metaphone) skipDouble(index int, c rune) int {
+	if m.charAt(index+1) == c {
+		return index + 2
+	}
+	return index + 1
+}
+
+func (m *metaphone) handleC(index int) int {
+	switch {
+	case m.conditionC0(index):
+		// Various Germanic spellings: "bacher", "macher".
+		m.add("K", "K")
+		return index + 2
+	case index == 0 && m.contains(index, 6, "CAESAR"):
+		m.add("S", "S")
+		return index + 2
+	case m.contains(index, 2, "CH"):
+		return m.handleCH(index)
+	case m.contains(index, 2, "CZ") && !m.contains(index-2, 4, "WICZ"):
+		m.add("S", "X")
+		return index + 2
+	case m.contains(index+1, 3, "CIA"):
+		m.add("X", "X")
+		return index + 3
+	case m.contains(index, 2, "CC") && !(index == 1 && m.charAt(0) == 'M'):
+		return m.handleCC(index)
+	case m.contains(index, 2, "CK", "CG", "CQ"):
+		m.add("K", "K")
+		return index + 2
+	case m.contains(index, 2, "CI", "CE", "CY"):
+		if m.contains(index, 3, "CIO", "CIE", "CIA") {
+			m.add("S", "X")
+		} else {
+			m.add("S", "S")
+		}
+		return index + 2
+	}
+	m.add("K", "K")
+	switch {
+	case m.contains(index+1, 2, " C", " Q", " G"):
+		return index + 3
+	case m.contains(index+1, 1, "C", "K", "Q") && !m.contains(index+1, 2, "CE", "CI"):
+		return index + 2
+	}
+	return index + 1
+}
+
+func (m *metaphone) conditionC0(index int) bool {
+	switch {
+	case m.contains(index, 4, "CHIA"):
+		return true
+	case index <= 1:
+		return false
+	case m.isVowel(index - 2):
+		return false
+	case !m.contains(index-1, 3, "ACH"):
+		return false
+	}
+	c := m.charAt(index + 2)
+	return (c != 'I' && c != 'E') || m.contains(index-2, 6, "BACHER", "MACHER")
+}
+
+func (m *metaphone) handleCC(index int) int {
+	if m.contains(index+2, 1, "I", "E", "H") && !m.contains(index+2, 2, "HU") {
+		// "bellocchio" but not "bacchus"; "accident", "accede", "succeed".
+		if (index == 1 && m.charAt(index-1) == 'A') || m.contains(index-1, 5, "UCCEE", "UCCES") {
+			m.add("KS", "KS")
+		} else {
+			m.add("X", "X")
+		}
+		return index + 3
+	}
+	// Pierce's rule.
+	m.add("K", "K")
+	return index + 2
+}
+
+func (m *metaphone) handleCH(index int) int {
+	switch {
+	case index > 0 && m.contains(index, 4, "CHAE"):
+		// "Michael".
+		m.add("K", "X")
+	case m.conditionCH0(index), m.conditionCH1(index):
+		// Greek roots ("chemistry", "chorus") and Germanic spellings ("Bach", "orchestra").
+		m.add("K", "K")
+	case index > 0:
+		if m.contains(0, 2, "MC") {
+			m.add("K", "K")
+		} else {
+			m.add("X", "K")
+		}
+	default:
+		m.add("X", "X")
+	}
+	return index + 2
+}
+
+func (m *metaphone) conditionCH0(index int) bool {
+	if index != 0 {
+		return false
+	}
+	if !m.contains(index+1, 5, "HARAC", "HARIS") && !m.contains(index+1, 3, "HOR", "HYM", "HIA", "HEM") {
+		return false
+	}
+	return !m.contains(0, 5, "CHORE")
+}
+
+func (m *metaphone) conditionCH1(index int) bool {
+	return m.contains(0, 4, "VAN ", "VON ") || m.contains(0, 3, "SCH") ||
+		m.contains(index-2, 6, "ORCHES", "ARCHIT", "ORCHID") ||
+		m.contains(index+2, 1, "T", "S") ||
+		((m.contains(index-1, 1, "A", "O", "U", "E") || index == 0) &&
+			(m.contains(index+2, 1, "L", "R", "N", "M", "B", "H", "F", "V", "W", " ") || index+1 == len(m.value)-1))
+}
+
+func (m *metaphone) handleD(index int) int {
+	switch {
+	case m.contains(index, 2, "DG"):
+		if m.contains(index+2, 1, "I", "E", "Y") {
+			// "edge".
+			m.add("J", "J")
+			return index + 3
+		}
+		// "edgar".
+		m.add("TK", "TK")
+		return index + 2
+	case m.contains(index, 2, "DT", "DD"):
+		m.add("T", "T")
+		return index + 2
+	}
+	m.add("T", "T")
+	return index + 1
+}
+
+func (m *metaphone) handleG(index int) int {
+	switch {
+	case m.charAt(index+1) == 'H':
+		return m.handleGH(index)
+	case m.charAt(index+1) == 'N':
+		switch {
+		case index == 1 && m.isVowel(0) && !m.slavoGermanic:
+			m.add("KN", "N")
+		case !m.contains(index+2, 2, "EY") && m.charAt(index+1) != 'Y' && !m.slavoGermanic:
+			m.add("N", "KN")
+		default:
+			m.add("KN", "KN")
+		}
+		return index + 2
+	case m.contains(index+1, 2, "LI") && !m.slavoGermanic:
+		m.add("KL", "L")
+		return index + 2
+	case index == 0 && (m.charAt(index+1) == 'Y' || m.contains(index+1, 2, "ES", "EP", "EB", "EL", "EY", "IB", "IL", "IN", "IE", "EI", "ER")):
+		m.add("K", "J")
+		return index + 2
+	case (m.contains(index+1, 2, "ER") || m.charAt(index+1) == 'Y') &&
+		!m.contains(0, 6, "DANGER", "RANGER", "MANGER") &&
+		!m.contains(index-1, 1, "E", "I") && !m.contains(index-1, 3, "RGY", "OGY"):
+		m.add("K", "J")
+		return index + 2
+	case m.contains(index+1, 1, "E", "I", "Y") || m.contains(index-1, 4, "AGGI", "OGGI"):
+		switch {
+		case m.contains(0, 4, "VAN ", "VON ") || m.contains(0, 3, "SCH") || m.contains(index+1, 2, "ET"):
+			m.add("K", "K")
+		case m.contains(index+1, 3, "IER"):
+			m.add("J", "J")
+		default:
+			m.add("J", "K")
+		}
+		return index + 2
+	case m.charAt(index+1) == 'G':
+		m.add("K", "K")
+		return index + 2
+	}
+	m.add("K", "K")
+	return index + 1
+}
+
+func (m *metaphone) handleGH(index int) int {
+	switch {
+	case index > 0 && !m.isVowel(index-1):
+		m.add("K", "K")
+	case index == 0:
+		if m.charAt(index+2) == 'I' {
+			m.add("J", "J")
+		} else {
+			m.add("K", "K")
+		}
+	case (index > 1 && m.contains(index-2, 1, "B", "H", "D")) ||
+		(index > 2 && m.contains(index-3, 1, "B", "H", "D")) ||
+		(index > 3 && m.contains(index-4, 1, "B", "H")):
+		// Silent: "hugh", "bough", "broughton".
+	default:
+		if index > 2 && m.charAt(index-1) == 'U' && m.contains(index-3, 1, "C", "G", "L", "R", "T") {
+			// "laugh", "cough", "tough".
+			m.add("F", "F")
+		} else if index > 0 && m.charAt(index-1) != 'I' {
+			m.add("K", "K")
+		}
+	}
+	return index + 2
+}
+
+func (m *metaphone) handleH(index int) int {
+	// Only keep an H between vowels or at the start before a vowel.
+	if (index == 0 || m.isVowel(index-1)) && m.isVowel(index+1) {
+		m.add("H", "H")
+		return index + 2
+	}
+	return index + 1
+}
+
+func (m *metaphone) handleJ(index int) int {
+	if m.contains(index, 4, "JOSE") || m.contains(0, 4, "SAN ") {
+		// Spanish: "Jose", "San Jacinto".
+		if (index == 0 && m.charAt(index+4) == ' ') || len(m.value) == 4 || m.contains(0, 4, "SAN ") {
+			m.add("H", "H")
+		} else {
+			m.add("J", "H")
+		}
+		return index + 1
+	}
+	switch {
+	case index == 0:
+		m.add("J", "A")
+	case m.isVowel(index-1) && !m.slavoGermanic && (m.charAt(index+1) == 'A' || m.charAt(index+1) == 'O'):
+		m.add("J", "H")
+	case index == len(m.value)-1:
+		m.addPrimary("J")
+	case !m.contains(index+1, 1, "L", "T", "K", "S", "N", "M", "B", "Z") && !m.contains(index-1, 1, "S", "K", "L"):
+		m.add("J", "J")
+	}
+	return m.skipDouble(index, 'J')
+}
+
+func (m *metaphone) handleL(index int) int {
+	if m.charAt(index+1) == 'L' {
+		if m.conditionL0(index) {
+			// Spanish: "cabrillo", "gallegos".
+			m.addPrimary("L")
+		} else {
+			m.add("L", "L")
+		}
+		return index + 2
+	}
+	m.add("L", "L")
+	return index + 1
+}
+
+func (m *metaphone) conditionL0(index int) bool {
+	n := len(m.value)
+	if index == n-3 && m.contains(index-1, 4, "ILLO", "ILLA", "ALLE") {
+		return true
+	}
+	return (m.contains(n-2, 2, "AS", "OS") || m.contains(n-1, 1, "A", "O")) && m.contains(index-1, 4, "ALLE")
+}
+
+func (m *metaphone) conditionM0(index int) bool {
+	if m.charAt(index+1) == 'M' {
+		return true
+	}
+	// "dumb", "thumb".
+	return m.contains(index-1, 3, "UMB") && (index+1 == len(m.value)-1 || m.contains(index+2, 2, "ER"))
+}
+
+func (m *metaphone) handleP(index int) int {
+	if m.charAt(index+1) == 'H' {
+		m.add("F", "F")
+		return index + 2
+	}
+	m.add("P", "P")
+	if m.contains(index+1, 1, "P", "B") {
+		return index + 2
+	}
+	return index + 1
+}
+
+func (m *metaphone) handleR(index int) int {
+	if index == len(m.value)-1 && !m.slavoGermanic && m.contains(index-2, 2, "IE") && !m.contains(index-4, 2, "ME", "MA") {
+		// French: "rogier", but not "hochmeier".
+		m.addAlternate("R")
+	} else {
+		m.add("R", "R")
+	}
+	return m.skipDouble(index, 'R')
+}
+
+func (m *metaphone) handleS(index int) int {
+	switch {
+	case m.contains(index-1, 3, "ISL", "YSL"):
+		// Silent: "island", "carlisle".
+		return index + 1
+	case index == 0 && m.contains(index, 5, "SUGAR"):
+		m.add("X", "S")
+		return index + 1
+	case m.contains(index, 2, "SH"):
+		if m.contains(index+1, 4, "HEIM", "HOEK", "HOLM", "HOLZ") {
+			m.add("S", "S")
+		} else {
+			m.add("X", "X")
+		}
+		return index + 2
+	case m.contains(index, 3, "SIO", "SIA") || m.contains(index, 4, "SIAN"):
+		if m.slavoGermanic {
+			m.add("S", "S")
+		} else {
+			m.add("S", "X")
+		}
+		return index + 3
+	case (index == 0 && m.contains(index+1, 1, "M", "N", "L", "W")) || m.contains(index+1, 1, "Z"):
+		// "smith" / "schmidt", "snider" / "schneider".
+		m.add("S", "X")
+		if m.contains(index+1, 1, "Z") {
+			return index + 2
+		}
+		return index + 1
+	case m.contains(index, 2, "SC"):
+		return m.handleSC(index)
+	}
+	if index == len(m.value)-1 && m.contains(index-2, 2, "AI", "OI") {
+		// French: "resnais", "artois".
+		m.addAlternate("S")
+	} else {
+		m.add("S", "S")
+	}
+	if m.contains(index+1, 1, "S", "Z") {
+		return index + 2
+	}
+	return index + 1
+}
+
+func (m *metaphone) handleSC(index int) int {
+	switch {
+	case m.charAt(index+2) == 'H':
+		switch {
+		case m.contains(index+3, 2, "OO", "ER", "EN", "UY", "ED", "EM"):
+			// Dutch: "school", "schooner"; German: "schermerhorn".
+			if m.contains(index+3, 2, "ER", "EN") {
+				m.add("X", "SK")
+			} else {
+				m.add("SK", "SK")
+			}
+		case index == 0 && !m.isVowel(3) && m.charAt(3) != 'W':
+			m.add("X", "S")
+		default:
+			m.add("X", "X")
+		}
+	case m.contains(index+2, 1, "I", "E", "Y"):
+		m.add("S", "S")
+	default:
+		m.add("SK", "SK")
+	}
+	return index + 3
+}
+
+func (m *metaphone) handleT(index int) int {
+	switch {
+	case m.contains(index, 4, "TION"), m.contains(index, 3, "TIA", "TCH"):
+		m.add("X", "X")
+		return index + 3
+	case m.contains(index, 2, "TH") || m.contains(index, 3, "TTH"):
+		if m.contains(index+2, 2, "OM", "AM") || m.contains(0, 4, "VAN ", "VON ") || m.contains(0, 3, "SCH") {
+			// "thomas", "thames".
+			m.add("T", "T")
+		} else {
+			m.add("0", "T")
+		}
+		return index + 2
+	}
+	m.add("T", "T")
+	if m.contains(index+1, 1, "T", "D") {
+		return index + 2
+	}
+	return index + 1
+}
+
+func (m *metaphone) handleW(index int) int {
+	if m.contains(index, 2, "WR") {
+		// "wright".
+		m.add("R", "R")
+		return index + 2
+	}
+	switch {
+	case index == 0 && (m.isVowel(index+1) || m.contains(index, 2, "WH")):
+		if m.isVowel(index + 1) {
+			// "Wasserman" should match "Vasserman".
+			m.add("A", "F")
+		} else {
+			m.add("A", "A")
+		}
+		return index + 1
+	case (index == len(m.value)-1 && m.isVowel(index-1)) ||
+		m.contains(index-1, 5, "EWSKI", "EWSKY", "OWSKI", "OWSKY") || m.contains(0, 3, "SCH"):
+		// Polish: "filipowicz".
+		m.addAlternate("F")
+		return index + 1
+	case m.contains(index, 4, "WICZ", "WITZ"):
+		m.add("TS", "FX")
+		return index + 4
+	}
+	return index + 1
+}
+
+func (m *metaphone) handleX(index int) int {
+	if index == 0 {
+		m.add("S", "S")
+		return index + 1
+	}
+	if !(index == len(m.value)-1 && (m.contains(index-3, 3, "IAU", "EAU") || m.contains(index-2, 2, "AU", "OU"))) {
+		// French: "breaux" is silent.
+		m.add("KS", "KS")
+	}
+	if m.contains(index+1, 1, "C", "X") {
+		return index + 2
+	}
+	return index + 1
+}
+
+func (m *metaphone) handleZ(index int) int {
+	if m.charAt(index+1) == 'H' {
+		// Chinese pinyin: "zhao".
+		m.add("J", "J")
+		return index + 2
+	}
+	if m.contains(index+1, 2, "ZO", "ZI", "ZA") || (m.slavoGermanic && index > 0 && m.charAt(index-1) != 'T') {
+		m.add("S", "TS")
+	} else {
+		m.add("S", "S")
+	}
+	return m.skipDouble(index, 'Z')
+}
+
+// BuildPhoneticIndex builds the sound-alike index used when SuggestionOptions.Phonetic
+// is set. Like BuildIndex, it must be called after all words have been added.
+func (d *Dictionary) BuildPhoneticIndex() {
+	d.phonetic = newPhoneticIndex(suggestibleWords(d))
+}
+
+// phoneticWords returns the sound-alike index, built once on first use if
+// BuildPhoneticIndex was not called, rather than for every misspelled word.
+func (d *Dictionary) phoneticWords() phoneticIndex {
+	d.phoneticOnce.Do(func() {
+		if d.phonetic == nil {
+			d.phonetic = newPhoneticIndex(suggestibleWords(d))
+		}
+	})
+	return d.phonetic
+}
+
+// phoneticIndex maps Double Metaphone keys to the dictionary words that have them.
+type phoneticIndex map[string][]string
+
+// newPhoneticIndex indexes every word under its primary and alternate keys.
+func newPhoneticIndex(words []string) phoneticIndex {
+	index := make(phoneticIndex)
+	for _, word := range words {
+		index.add(word)
+	}
+	return index
+}
+
+// add indexes word under its primary and alternate keys.
+func (p phoneticIndex) add(word string) {
+	primary, alternate := doubleMetaphone(word)
+	if primary != "" {
+		p[primary] = append(p[primary], word)
+	}
+	if alternate != "" && alternate != primary {
+		p[alternate] = append(p[alternate], word)
+	}
+}
+
+// lookup returns the words that share a key with word, without duplicates.
+func (p phoneticIndex) lookup(word string) []string {
+	primary, alternate := doubleMetaphone(word)
+	matches := p[primary]
+	if alternate == primary || alternate == "" {
+		return matches
+	}
+	seen := make(map[string]struct{}, len(matches))
+	result := append([]string(nil), matches...)
+	for _, m := range matches {
+		seen[m] = struct{}{}
+	}
+	for _, m := range p[alternate] {
+		if _, ok := seen[m]; !ok {
+			result = append(result, m)
+		}
+	}
+	return result
+}
@@ -0,0 +1,109 @@
+package spellchecker
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDoubleMetaphone(t *testing.T) {
+	testCases := []struct {
+		word               string
+		primary, alternate string
+	}{
+		{"", "", ""},
+		{"phonetic", "FNTK", "FNTK"},
+		{"fonetik", "FNTK", "FNTK"},
+		{"night", "NT", "NT"},
+		{"knight", "NT", "NT"},
+		{"nite", "NT", "NT"},
+		{"Thomas", "TMS", "TMS"},
+		{"Smith", "SM0", "XMT"},
+		{"Schmidt", "XMT", "SMT"},
+		{"Michael", "MKL", "MXL"},
+		{"Jose", "HS", "HS"},
+		{"Xavier", "SF", "SFR"},
+		{"Caesar", "SSR", "SSR"},
+		{"Wasserman", "ASRM", "FSRM"},
+		{"laugh", "LF", "LF"},
+		{"edge", "AJ", "AJ"},
+		// Keys are capped at four characters.
+		{"internationalization", "ANTR", "ANTR"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.word, func(t *testing.T) {
+			primary, alternate := doubleMetaphone(tc.word)
+			if primary != tc.primary || alternate != tc.alternate {
+				t.Errorf("doubleMetaphone(%q) = %q, %q; want %q, %q", tc.word, primary, alternate, tc.primary, tc.alternate)
+			}
+		})
+	}
+}
+
+func TestPhoneticIndexLookup(t *testing.T) {
+	index := newPhoneticIndex([]string{"smith", "schmidt", "smyth", "night"})
+
+	// "smith" is SM0 / XMT, so it finds "smyth" through the primary key and
+	// "schmidt" through the alternate one, each only once.
+	got := index.lookup("smith")
+	want := []string{"smith", "smyth", "schmidt"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("lookup(%q) = %v; want %v", "smith", got, want)
+	}
+	if got := index.lookup("table"); len(got) != 0 {
+		t.Errorf("lookup(%q) = %v; want nothing", "table", got)
+	}
+}
+
+func TestGenerateSuggestionsPhonetic(t *testing.T) {
+	newTestDictionary := func() *Dictionary {
+		return &Dictionary{
+			Words: map[string]struct{}{
+				"phonetic": {}, "frenetic": {}, "night": {}, "note": {}, "net": {}, "nice": {}, "knight": {},
+			},
+			NoSuggest: map[string]struct{}{"knight": {}},
+		}
+	}
+
+	testCases := []struct {
+		word     string
+		phonetic bool
+		expected []string
+	}{
+		{"fonetik", false, []string{}},
+		{"fonetik", true, []string{"phonetic"}},
+		{"nite", false, []string{"note", "nice", "net"}},
+		// "note" sounds alike and is as close as "nice"; "night" is three edits away.
+		// "knight" sounds alike too but is marked NoSuggest.
+		{"nite", true, []string{"note", "nice", "net", "night"}},
+	}
+
+	for _, tc := range testCases {
+		for _, indexed := range []bool{false, true} {
+			dictionary := newTestDictionary()
+			if indexed {
+				dictionary.BuildIndex()
+				dictionary.BuildPhoneticIndex()
+			}
+			got := generateSuggestions(tc.word, dictionary, SuggestionOptions{Phonetic: tc.phonetic})
+			if len(got) == 0 && len(tc.expected) == 0 {
+				continue
+			}
+			if !reflect.DeepEqual(got, tc.expected) {
+				t.Errorf("generateSuggestions(%q, phonetic=%v, indexed=%v) = %v; want %v", tc.word, tc.phonetic, indexed, got, tc.expected)
+			}
+		}
+	}
+
+	// Without BuildPhoneticIndex, the index is built on first use and then reused.
+	dictionary := newTestDictionary()
+	generateSuggestions("fonetik", dictionary, SuggestionOptions{Phonetic: true})
+	built := dictionary.phonetic
+	if built == nil {
+		t.Fatal("the phonetic index was not kept after its first use")
+	}
+	generateSuggestions("nite", dictionary, SuggestionOptions{Phonetic: true})
+	if reflect.ValueOf(dictionary.phonetic).Pointer() != reflect.ValueOf(built).Pointer() {
+		t.Error("the phonetic index was built again")
+	}
+}
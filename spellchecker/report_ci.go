@@ -0,0 +1,137 @@
+package spellchecker
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+)
+
+// generateGitHubReport writes one GitHub Actions workflow command per typo. When the
+// output is printed by a workflow step, each typo is shown as a warning annotation on
+// its line in the pull request diff and the run summary. A file or directory that could
+// not be checked is an error annotation.
+func generateGitHubReport(writer io.Writer, results map[string][]MisspelledWord, summary ScanSummary) error {
+	for _, path := range sortedFiles(results) {
+		file := filepath.ToSlash(filepath.Clean(path))
+		for _, m := range results[path] {
+			title := githubTitles[m.rule()]
+			_, err := fmt.Fprintf(writer, "::warning file=%s,line=%d,col=%d,endColumn=%d,title=%s::%s\n",
+				escapeGitHubProperty(file), m.LineNumber, m.Column,
+				m.Column+utf8.RuneCountInString(m.Word)-1,
+				escapeGitHubProperty(title), escapeGitHubData(typoMessage(m)))
+			if err != nil {
+				return err
+			}
+		}
+	}
+	for _, e := range summary.Errors {
+		_, err := fmt.Fprintf(writer, "::error file=%s,title=%s::%s\n",
+			escapeGitHubProperty(filepath.ToSlash(filepath.Clean(e.Path))),
+			escapeGitHubProperty("Could not check file"), escapeGitHubData(e.Err.Error()))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// githubTitles are the annotation titles of each rule.
+var githubTitles = map[string]string{
+	RuleUnknownWord:     "Unknown word",
+	RuleForbiddenWord:   "Forbidden word",
+	RuleUnusedDirective: "Unused directive",
+}
+
+// escapeGitHubData escapes the message of a workflow command.
+func escapeGitHubData(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+// escapeGitHubProperty escapes a property value of a workflow command, which also
+// must not contain the property separators.
+func escapeGitHubProperty(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(s)
+}
+
+// gitlabIssue is an entry of a GitLab Code Quality report.
+type gitlabIssue struct {
+	Description string         `json:"description"`
+	CheckName   string         `json:"check_name"`
+	Fingerprint string         `json:"fingerprint"`
+	Severity    string         `json:"severity"`
+	Location    gitlabLocation `json:"location"`
+}
+
+type gitlabLocation struct {
+	Path  string      `json:"path"`
+	Lines gitlabLines `json:"lines"`
+}
+
+type gitlabLines struct {
+	Begin int `json:"begin"`
+}
+
+// gitlabFileError is the check name of the issues of files that could not be checked.
+const gitlabFileError = "file-error"
+
+// gitlabSeverities are the Code Quality severities of each rule.
+var gitlabSeverities = map[string]string{
+	RuleUnknownWord:     "minor",
+	RuleForbiddenWord:   "major",
+	RuleUnusedDirective: "info",
+}
+
+// generateGitLabReport writes a GitLab Code Quality report: a JSON array with one issue
+// per typo, to be uploaded as an artifacts:reports:codequality file. Forbidden words
+// are major issues, other typos minor ones and unused directives only informational.
+// A file or directory that could not be checked is a critical issue.
+func generateGitLabReport(writer io.Writer, results map[string][]MisspelledWord, summary ScanSummary) error {
+	issues := []gitlabIssue{}
+	for _, path := range sortedFiles(results) {
+		file := filepath.ToSlash(filepath.Clean(path))
+		// occurrences numbers repeated typos so that each has its own fingerprint.
+		occurrences := make(map[string]int)
+		for _, m := range results[path] {
+			severity := gitlabSeverities[m.rule()]
+			key := m.rule() + "\x00" + m.Word
+			occurrences[key]++
+			issues = append(issues, gitlabIssue{
+				Description: typoMessage(m),
+				CheckName:   m.rule(),
+				Fingerprint: gitlabFingerprint(file, key, occurrences[key]),
+				Severity:    severity,
+				Location:    gitlabLocation{Path: file, Lines: gitlabLines{Begin: m.LineNumber}},
+			})
+		}
+	}
+	errorCounts := make(map[string]int)
+	for _, e := range summary.Errors {
+		file := filepath.ToSlash(filepath.Clean(e.Path))
+		errorCounts[file]++
+		issues = append(issues, gitlabIssue{
+			Description: e.Err.Error(),
+			CheckName:   gitlabFileError,
+			Fingerprint: gitlabFingerprint(file, gitlabFileError, errorCounts[file]),
+			Severity:    "critical",
+			Location:    gitlabLocation{Path: file, Lines: gitlabLines{Begin: 1}},
+		})
+	}
+
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+	return encoder.Encode(issues)
+}
+
+// gitlabFingerprint identifies the nth occurrence of a typo in a file. Line numbers are
+// left out so that an issue keeps its fingerprint when unrelated lines are added above
+// it, which lets GitLab tell new typos from existing ones in merge requests.
+func gitlabFingerprint(file, key string, n int) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s\x00%s\x00%d", file, key, n)))
+	return hex.EncodeToString(sum[:16])
+}
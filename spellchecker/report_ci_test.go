@@ -0,0 +1,98 @@
+package spellchecker
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+)
+
+func TestGenerateGitHubReport(t *testing.T) {
+	results := map[string][]MisspelledWord{
+		filepath.Join("docs", "a,b.md"): {
+			{Word: "wrld", LineNumber: 3, Column: 7, Suggestions: []string{"world", "word"}},
+		},
+		"notes.txt": {
+			{Word: "colour", LineNumber: 1, Column: 1, Rule: RuleForbiddenWord},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := generateGitHubReport(&buf, results, ScanSummary{}); err != nil {
+		t.Fatalf("generateGitHubReport failed: %v", err)
+	}
+	want := "::warning file=docs/a%2Cb.md,line=3,col=7,endColumn=10,title=Unknown word::\"wrld\" appears to be a typo. Did you mean: world, word?\n" +
+		"::warning file=notes.txt,line=1,col=1,endColumn=6,title=Forbidden word::\"colour\" is a forbidden word.\n"
+	if got := buf.String(); got != want {
+		t.Errorf("GitHub report mismatch.\nGOT:\n%s\nWANT:\n%s", got, want)
+	}
+}
+
+func TestEscapeGitHubData(t *testing.T) {
+	if got, want := escapeGitHubData("100% sure\nnext: line"), "100%25 sure%0Anext: line"; got != want {
+		t.Errorf("escapeGitHubData() = %q; want %q", got, want)
+	}
+	if got, want := escapeGitHubProperty("C:\\a,b"), "C%3A\\a%2Cb"; got != want {
+		t.Errorf("escapeGitHubProperty() = %q; want %q", got, want)
+	}
+}
+
+func TestGenerateGitLabReport(t *testing.T) {
+	results := map[string][]MisspelledWord{
+		"./docs/a.md": {
+			{Word: "wrld", LineNumber: 3, Column: 7, Suggestions: []string{"world"}},
+			{Word: "wrld", LineNumber: 9, Column: 1, Suggestions: []string{"world"}},
+			{Word: "colour", LineNumber: 10, Column: 1, Rule: RuleForbiddenWord},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := generateGitLabReport(&buf, results, ScanSummary{}); err != nil {
+		t.Fatalf("generateGitLabReport failed: %v", err)
+	}
+	var issues []gitlabIssue
+	if err := json.Unmarshal(buf.Bytes(), &issues); err != nil {
+		t.Fatalf("GitLab report is not valid JSON: %v\n%s", err, buf.String())
+	}
+	if len(issues) != 3 {
+		t.Fatalf("Expected 3 issues, got %d", len(issues))
+	}
+
+	first := issues[0]
+	if first.CheckName != "unknown-word" || first.Severity != "minor" || first.Location != (gitlabLocation{Path: "docs/a.md", Lines: gitlabLines{Begin: 3}}) {
+		t.Errorf("Unexpected issue: %+v", first)
+	}
+	if first.Description != `"wrld" appears to be a typo. Did you mean: world?` {
+		t.Errorf("Unexpected description: %q", first.Description)
+	}
+	if issues[2].CheckName != "forbidden-word" || issues[2].Severity != "major" {
+		t.Errorf("Unexpected issue for a forbidden word: %+v", issues[2])
+	}
+
+	// Repeated typos get distinct fingerprints that do not depend on the line number.
+	if first.Fingerprint == issues[1].Fingerprint {
+		t.Errorf("Repeated typos share fingerprint %q", first.Fingerprint)
+	}
+	moved := map[string][]MisspelledWord{"docs/a.md": {{Word: "wrld", LineNumber: 40, Column: 2}}}
+	buf.Reset()
+	if err := generateGitLabReport(&buf, moved, ScanSummary{}); err != nil {
+		t.Fatalf("generateGitLabReport failed: %v", err)
+	}
+	var movedIssues []gitlabIssue
+	if err := json.Unmarshal(buf.Bytes(), &movedIssues); err != nil {
+		t.Fatalf("GitLab report is not valid JSON: %v", err)
+	}
+	if movedIssues[0].Fingerprint != first.Fingerprint {
+		t.Errorf("Fingerprint changed when the typo moved: %q != %q", movedIssues[0].Fingerprint, first.Fingerprint)
+	}
+}
+
+func TestGenerateGitLabReportNoTypos(t *testing.T) {
+	var buf bytes.Buffer
+	if err := generateGitLabReport(&buf, map[string][]MisspelledWord{}, ScanSummary{}); err != nil {
+		t.Fatalf("generateGitLabReport failed: %v", err)
+	}
+	if got := buf.String(); got != "[]\n" {
+		t.Errorf("Expected an empty array, got %q", got)
+	}
+}
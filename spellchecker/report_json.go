@@ -0,0 +1,100 @@
+package spellchecker
+
+import (
+	"encoding/json"
+	"io"
+	"sort"
+)
+
+// jsonReportVersion is incremented whenever a field of the JSON report is removed or
+// changes meaning. Adding fields does not change the version.
+const jsonReportVersion = 1
+
+// jsonReport is the document written by --format json.
+type jsonReport struct {
+	Version int               `json:"version"`
+	Summary jsonReportSummary `json:"summary"`
+	Files   []jsonReportFile  `json:"files"`
+	Errors  []jsonReportError `json:"errors"`
+}
+
+type jsonReportSummary struct {
+	FilesScanned   int `json:"filesScanned"`
+	FilesSkipped   int `json:"filesSkipped"`
+	FilesWithTypos int `json:"filesWithTypos"`
+	TotalTypos     int `json:"totalTypos"`
+	DictionarySize int `json:"dictionarySize"`
+}
+
+type jsonReportFile struct {
+	Path  string           `json:"path"`
+	Typos []jsonReportTypo `json:"typos"`
+}
+
+// jsonReportError is a file or directory that could not be checked.
+type jsonReportError struct {
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+type jsonReportTypo struct {
+	Word        string   `json:"word"`
+	Line        int      `json:"line"`
+	Column      int      `json:"column"`
+	Suggestions []string `json:"suggestions"`
+	Rule        string   `json:"rule"`
+}
+
+// generateJSONReport writes results as a single JSON document. Files are sorted by path
+// and typos keep their order in the file; empty lists are written as [] rather than
+// null, so consumers never need to special-case a clean run.
+func generateJSONReport(writer io.Writer, results map[string][]MisspelledWord, summary ScanSummary) error {
+	report := jsonReport{
+		Version: jsonReportVersion,
+		Summary: jsonReportSummary{
+			FilesScanned:   summary.FilesScanned,
+			FilesSkipped:   summary.FilesSkipped,
+			FilesWithTypos: len(results),
+			DictionarySize: summary.DictionarySize,
+		},
+		Files:  make([]jsonReportFile, 0, len(results)),
+		Errors: make([]jsonReportError, 0, len(summary.Errors)),
+	}
+	for _, e := range summary.Errors {
+		report.Errors = append(report.Errors, jsonReportError{Path: e.Path, Message: e.Err.Error()})
+	}
+	for _, path := range sortedFiles(results) {
+		words := results[path]
+		file := jsonReportFile{Path: path, Typos: make([]jsonReportTypo, 0, len(words))}
+		for _, m := range words {
+			suggestions := m.Suggestions
+			if suggestions == nil {
+				suggestions = []string{}
+			}
+			file.Typos = append(file.Typos, jsonReportTypo{
+				Word:        m.Word,
+				Line:        m.LineNumber,
+				Column:      m.Column,
+				Suggestions: suggestions,
+				Rule:        m.rule(),
+			})
+		}
+		report.Summary.TotalTypos += len(words)
+		report.Files = append(report.Files, file)
+	}
+
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+	return encoder.Encode(report)
+}
+
+// sortedFiles returns the file paths of results in lexical order.
+func sortedFiles(results map[string][]MisspelledWord) []string {
+	files := make([]string, 0, len(results))
+	for path := range results {
+		files = append(files, path)
+	}
+	sort.Strings(files)
+	return files
+}
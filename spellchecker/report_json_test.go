@@ -0,0 +1,91 @@
+package spellchecker
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/fs"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestGenerateJSONReport(t *testing.T) {
+	results := map[string][]MisspelledWord{
+		"docs/b.md": {
+			{Word: "wrod", LineNumber: 2, Column: 10, Suggestions: []string{"word", "world"}},
+			{Word: "xyzzy", LineNumber: 3, Column: 1},
+		},
+		"a.txt": {
+			{Word: "errror", LineNumber: 1, Column: 5, Suggestions: []string{"error"}},
+		},
+	}
+	summary := ScanSummary{FilesScanned: 4, FilesSkipped: 1, DictionarySize: 1000, Errors: []FileError{{Path: "secret.txt", Err: fs.ErrPermission}}}
+
+	var buf bytes.Buffer
+	if err := generateJSONReport(&buf, results, summary); err != nil {
+		t.Fatalf("generateJSONReport failed: %v", err)
+	}
+
+	var got jsonReport
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("JSON report is not valid JSON: %v\n%s", err, buf.String())
+	}
+	want := jsonReport{
+		Version: 1,
+		Summary: jsonReportSummary{FilesScanned: 4, FilesSkipped: 1, FilesWithTypos: 2, TotalTypos: 3, DictionarySize: 1000},
+		Files: []jsonReportFile{
+			{Path: "a.txt", Typos: []jsonReportTypo{
+				{Word: "errror", Line: 1, Column: 5, Suggestions: []string{"error"}, Rule: "unknown-word"},
+			}},
+			{Path: "docs/b.md", Typos: []jsonReportTypo{
+				{Word: "wrod", Line: 2, Column: 10, Suggestions: []string{"word", "world"}, Rule: "unknown-word"},
+				{Word: "xyzzy", Line: 3, Column: 1, Suggestions: []string{}, Rule: "unknown-word"},
+			}},
+		},
+		Errors: []jsonReportError{{Path: "secret.txt", Message: "permission denied"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("JSON report mismatch.\nGOT:  %+v\nWANT: %+v", got, want)
+	}
+
+	// The field names are the documented schema.
+	for _, field := range []string{`"version"`, `"filesScanned"`, `"filesSkipped"`, `"totalTypos"`, `"dictionarySize"`, `"line"`, `"column"`, `"suggestions": []`} {
+		if !strings.Contains(buf.String(), field) {
+			t.Errorf("JSON report missing %s:\n%s", field, buf.String())
+		}
+	}
+}
+
+func TestGenerateJSONReportNoTypos(t *testing.T) {
+	var buf bytes.Buffer
+	if err := generateJSONReport(&buf, map[string][]MisspelledWord{}, ScanSummary{FilesScanned: 2}); err != nil {
+		t.Fatalf("generateJSONReport failed: %v", err)
+	}
+	if !strings.Contains(buf.String(), `"files": []`) || !strings.Contains(buf.String(), `"errors": []`) {
+		t.Errorf("Expected empty files and errors lists, got:\n%s", buf.String())
+	}
+}
+
+func TestGenerateJSONReportCountsBinaryFiles(t *testing.T) {
+	dictionary := &Dictionary{Words: map[string]struct{}{"hello": {}, "world": {}}}
+	tempDir := t.TempDir()
+	createFile(t, tempDir, "notes.txt", "hello world")
+	createFile(t, tempDir, "image.dat", "hello\x00wrld")
+
+	results, summary, err := runConcurrentChecker(context.Background(), tempDir, dictionary, CheckOptions{})
+	if err != nil {
+		t.Fatalf("runConcurrentChecker failed: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := generateJSONReport(&buf, results, summary); err != nil {
+		t.Fatalf("generateJSONReport failed: %v", err)
+	}
+	var got jsonReport
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("JSON report is not valid JSON: %v\n%s", err, buf.String())
+	}
+	if got.Summary.FilesScanned != 1 || got.Summary.FilesSkipped != 1 {
+		t.Errorf("summary = %+v; want 1 file scanned and the binary file skipped", got.Summary)
+	}
+}
@@ -0,0 +1,231 @@
+package spellchecker
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	sarifVersion = "2.1.0"
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	// sarifSourceRoot is the base of relative artifact URIs; consumers such as code
+	// scanning services resolve it to the root of the checked-out repository.
+	sarifSourceRoot = "%SRCROOT%"
+)
+
+// sarifRules are the checks reported by the tool, one SARIF rule each. The index of a
+// rule in this list is its ruleIndex in results.
+var sarifRules = []sarifRule{
+	{
+		ID:                   RuleUnknownWord,
+		Name:                 "UnknownWord",
+		ShortDescription:     sarifMessage{Text: "Word not found in the dictionary."},
+		FullDescription:      sarifMessage{Text: "The word is neither in the dictionary nor in the personal dictionary and is probably misspelled."},
+		DefaultConfiguration: sarifConfiguration{Level: "warning"},
+	},
+	{
+		ID:                   RuleForbiddenWord,
+		Name:                 "ForbiddenWord",
+		ShortDescription:     sarifMessage{Text: "Word forbidden by the dictionary."},
+		FullDescription:      sarifMessage{Text: "The dictionary explicitly marks the word as incorrect, for example with the Hunspell FORBIDDENWORD flag."},
+		DefaultConfiguration: sarifConfiguration{Level: "warning"},
+	},
+	{
+		ID:                   RuleUnusedDirective,
+		Name:                 "UnusedDirective",
+		ShortDescription:     sarifMessage{Text: "Inline directive that suppresses nothing."},
+		FullDescription:      sarifMessage{Text: "A spellchecker: comment directive, or a word of a spellchecker:ignore list, does not suppress any typo and can be removed."},
+		DefaultConfiguration: sarifConfiguration{Level: "note"},
+	},
+}
+
+// sarifRuleIndex returns the index in sarifRules of the rule that reported m.
+func sarifRuleIndex(m MisspelledWord) int {
+	for i, rule := range sarifRules {
+		if rule.ID == m.rule() {
+			return i
+		}
+	}
+	return 0
+}
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool        sarifTool         `json:"tool"`
+	Invocations []sarifInvocation `json:"invocations"`
+	ColumnKind  string            `json:"columnKind"`
+	Results     []sarifResult     `json:"results"`
+}
+
+// sarifInvocation describes the run of the tool; files that could not be checked are
+// reported as notifications of it rather than as results.
+type sarifInvocation struct {
+	ExecutionSuccessful        bool                `json:"executionSuccessful"`
+	ToolExecutionNotifications []sarifNotification `json:"toolExecutionNotifications,omitempty"`
+}
+
+type sarifNotification struct {
+	Level     string                      `json:"level"`
+	Message   sarifMessage                `json:"message"`
+	Locations []sarifNotificationLocation `json:"locations"`
+}
+
+// sarifNotificationLocation locates a notification in a whole file, without a region.
+type sarifNotificationLocation struct {
+	PhysicalLocation struct {
+		ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	} `json:"physicalLocation"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name  string      `json:"name"`
+	Rules []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string             `json:"id"`
+	Name                 string             `json:"name"`
+	ShortDescription     sarifMessage       `json:"shortDescription"`
+	FullDescription      sarifMessage       `json:"fullDescription"`
+	DefaultConfiguration sarifConfiguration `json:"defaultConfiguration"`
+}
+
+type sarifConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	RuleIndex int             `json:"ruleIndex"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+	Fixes     []sarifFix      `json:"fixes,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           sarifRegion           `json:"region"`
+}
+
+type sarifArtifactLocation struct {
+	URI       string `json:"uri"`
+	URIBaseID string `json:"uriBaseId,omitempty"`
+}
+
+// sarifRegion is a span on one line; EndColumn is exclusive.
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn"`
+	EndColumn   int `json:"endColumn"`
+}
+
+type sarifFix struct {
+	Description     sarifMessage          `json:"description"`
+	ArtifactChanges []sarifArtifactChange `json:"artifactChanges"`
+}
+
+type sarifArtifactChange struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Replacements     []sarifReplacement    `json:"replacements"`
+}
+
+type sarifReplacement struct {
+	DeletedRegion   sarifRegion  `json:"deletedRegion"`
+	InsertedContent sarifMessage `json:"insertedContent"`
+}
+
+// generateSARIFReport writes results as a SARIF 2.1.0 log with a single run. Every typo
+// is a result located by line and column, counted in Unicode code points like the
+// other reports, and each suggestion is offered as a fix replacing the word. Files that
+// could not be checked make the invocation unsuccessful, with one error notification
+// each.
+func generateSARIFReport(writer io.Writer, results map[string][]MisspelledWord, summary ScanSummary) error {
+	invocation := sarifInvocation{ExecutionSuccessful: len(summary.Errors) == 0}
+	for _, e := range summary.Errors {
+		var location sarifNotificationLocation
+		location.PhysicalLocation.ArtifactLocation = sarifArtifact(e.Path)
+		invocation.ToolExecutionNotifications = append(invocation.ToolExecutionNotifications, sarifNotification{
+			Level:     "error",
+			Message:   sarifMessage{Text: e.Err.Error()},
+			Locations: []sarifNotificationLocation{location},
+		})
+	}
+	run := sarifRun{
+		Tool:        sarifTool{Driver: sarifDriver{Name: "spellchecker", Rules: sarifRules}},
+		Invocations: []sarifInvocation{invocation},
+		ColumnKind:  "unicodeCodePoints",
+		Results:     []sarifResult{},
+	}
+	for _, path := range sortedFiles(results) {
+		artifact := sarifArtifact(path)
+		for _, m := range results[path] {
+			region := sarifRegion{
+				StartLine:   m.LineNumber,
+				StartColumn: m.Column,
+				EndColumn:   m.Column + utf8.RuneCountInString(m.Word),
+			}
+			ruleIndex := sarifRuleIndex(m)
+			result := sarifResult{
+				RuleID:    sarifRules[ruleIndex].ID,
+				RuleIndex: ruleIndex,
+				Level:     sarifRules[ruleIndex].DefaultConfiguration.Level,
+				Message:   sarifMessage{Text: typoMessage(m)},
+				Locations: []sarifLocation{{PhysicalLocation: sarifPhysicalLocation{ArtifactLocation: artifact, Region: region}}},
+			}
+			for _, suggestion := range m.Suggestions {
+				replacement := MatchCase(m.Word, suggestion)
+				result.Fixes = append(result.Fixes, sarifFix{
+					Description: sarifMessage{Text: fmt.Sprintf("Replace with %q", replacement)},
+					ArtifactChanges: []sarifArtifactChange{{
+						ArtifactLocation: artifact,
+						Replacements:     []sarifReplacement{{DeletedRegion: region, InsertedContent: sarifMessage{Text: replacement}}},
+					}},
+				})
+			}
+			run.Results = append(run.Results, result)
+		}
+	}
+
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+	return encoder.Encode(sarifLog{Schema: sarifSchema, Version: sarifVersion, Runs: []sarifRun{run}})
+}
+
+// sarifArtifact returns the location of a checked file. Relative paths are made
+// relative to the source root; absolute paths become file URIs.
+func sarifArtifact(path string) sarifArtifactLocation {
+	slashed := filepath.ToSlash(path)
+	if filepath.IsAbs(path) {
+		if !strings.HasPrefix(slashed, "/") {
+			// A Windows path such as C:/docs/a.md.
+			slashed = "/" + slashed
+		}
+		return sarifArtifactLocation{URI: (&url.URL{Scheme: "file", Path: slashed}).String()}
+	}
+	slashed = filepath.ToSlash(filepath.Clean(path))
+	return sarifArtifactLocation{URI: (&url.URL{Path: slashed}).String(), URIBaseID: sarifSourceRoot}
+}
@@ -0,0 +1,108 @@
+package spellchecker
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestGenerateSARIFReport(t *testing.T) {
+	results := map[string][]MisspelledWord{
+		"docs/guide.md": {
+			{Word: "naïvly", LineNumber: 3, Column: 7, Suggestions: []string{"naïvely", "naively"}},
+			{Word: "colour", LineNumber: 4, Column: 1, Rule: RuleForbiddenWord},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := generateSARIFReport(&buf, results, ScanSummary{}); err != nil {
+		t.Fatalf("generateSARIFReport failed: %v", err)
+	}
+
+	var log sarifLog
+	if err := json.Unmarshal(buf.Bytes(), &log); err != nil {
+		t.Fatalf("SARIF report is not valid JSON: %v\n%s", err, buf.String())
+	}
+	if log.Version != "2.1.0" || len(log.Runs) != 1 {
+		t.Fatalf("Expected one SARIF 2.1.0 run, got version %q with %d runs", log.Version, len(log.Runs))
+	}
+	run := log.Runs[0]
+	if len(run.Tool.Driver.Rules) != 3 || run.ColumnKind != "unicodeCodePoints" {
+		t.Errorf("Unexpected tool or column kind: %+v, %q", run.Tool.Driver, run.ColumnKind)
+	}
+	if len(run.Results) != 2 {
+		t.Fatalf("Expected 2 results, got %d", len(run.Results))
+	}
+
+	typo := run.Results[0]
+	artifact := sarifArtifactLocation{URI: "docs/guide.md", URIBaseID: "%SRCROOT%"}
+	// The word is 6 code points long, so the region ends before column 13.
+	region := sarifRegion{StartLine: 3, StartColumn: 7, EndColumn: 13}
+	wantLocation := []sarifLocation{{PhysicalLocation: sarifPhysicalLocation{ArtifactLocation: artifact, Region: region}}}
+	if typo.RuleID != "unknown-word" || typo.RuleIndex != 0 || !reflect.DeepEqual(typo.Locations, wantLocation) {
+		t.Errorf("Unexpected result for a typo: %+v", typo)
+	}
+	if len(typo.Fixes) != 2 {
+		t.Fatalf("Expected one fix per suggestion, got %d", len(typo.Fixes))
+	}
+	replacement := typo.Fixes[0].ArtifactChanges[0].Replacements[0]
+	if replacement.DeletedRegion != region || replacement.InsertedContent.Text != "naïvely" {
+		t.Errorf("Unexpected fix: %+v", replacement)
+	}
+
+	forbidden := run.Results[1]
+	if forbidden.RuleID != "forbidden-word" || forbidden.RuleIndex != 1 || forbidden.Fixes != nil {
+		t.Errorf("Unexpected result for a forbidden word: %+v", forbidden)
+	}
+}
+
+func TestGenerateSARIFReportMatchesCase(t *testing.T) {
+	results := map[string][]MisspelledWord{
+		"notes.txt": {
+			{Word: "Teh", LineNumber: 1, Column: 1, Suggestions: []string{"the"}},
+			{Word: "WRLD", LineNumber: 1, Column: 5, Suggestions: []string{"world"}},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := generateSARIFReport(&buf, results, ScanSummary{}); err != nil {
+		t.Fatalf("generateSARIFReport failed: %v", err)
+	}
+	var log sarifLog
+	if err := json.Unmarshal(buf.Bytes(), &log); err != nil {
+		t.Fatalf("SARIF report is not valid JSON: %v\n%s", err, buf.String())
+	}
+	for i, want := range []string{"The", "WORLD"} {
+		fix := log.Runs[0].Results[i].Fixes[0]
+		if got := fix.ArtifactChanges[0].Replacements[0].InsertedContent.Text; got != want {
+			t.Errorf("fix of %q inserts %q; want %q", results["notes.txt"][i].Word, got, want)
+		}
+		if fix.Description.Text != `Replace with "`+want+`"` {
+			t.Errorf("Unexpected fix description: %q", fix.Description.Text)
+		}
+	}
+}
+
+func TestSARIFArtifact(t *testing.T) {
+	testCases := []struct {
+		path string
+		want sarifArtifactLocation
+	}{
+		{"./README.md", sarifArtifactLocation{URI: "README.md", URIBaseID: "%SRCROOT%"}},
+		{filepath.Join("docs", "my notes.md"), sarifArtifactLocation{URI: "docs/my%20notes.md", URIBaseID: "%SRCROOT%"}},
+	}
+	if filepath.Separator == '/' {
+		testCases = append(testCases, struct {
+			path string
+			want sarifArtifactLocation
+		}{"/src/app/main.go", sarifArtifactLocation{URI: "file:///src/app/main.go"}})
+	}
+
+	for _, tc := range testCases {
+		if got := sarifArtifact(tc.path); got != tc.want {
+			t.Errorf("sarifArtifact(%q) = %+v; want %+v", tc.path, got, tc.want)
+		}
+	}
+}
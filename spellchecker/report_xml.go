@@ -0,0 +1,161 @@
+package spellchecker
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// junitTestSuites is the root of a JUnit XML report, in the form understood by
+// Jenkins, GitLab, Azure Pipelines and most other CI systems.
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Errors   int              `xml:"errors,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Errors   int             `xml:"errors,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	ClassName string        `xml:"classname,attr"`
+	Name      string        `xml:"name,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	Error     *junitFailure `xml:"error,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// generateJUnitReport writes a JUnit XML report with one test case per checked file.
+// A file with typos is a failed test case whose failure lists every typo; a clean file
+// passes. A file or directory that could not be checked is a test case in error, so
+// the report fails then even though the command only exits with an error status for
+// it under --fail-on-error.
+func generateJUnitReport(writer io.Writer, results map[string][]MisspelledWord, summary ScanSummary) error {
+	suite := junitTestSuite{Name: "spellchecker"}
+	files := summary.Files
+	if files == nil {
+		// Without the list of checked files, report the files with typos.
+		files = sortedFiles(results)
+	}
+	for _, path := range files {
+		testCase := junitTestCase{ClassName: "spelling", Name: path}
+		if words := results[path]; len(words) > 0 {
+			testCase.Failure = &junitFailure{
+				Message: fmt.Sprintf("%d typos found", len(words)),
+				Type:    "spelling",
+				Text:    junitTypos(words),
+			}
+			suite.Failures++
+		}
+		suite.Cases = append(suite.Cases, testCase)
+	}
+	for _, e := range summary.Errors {
+		testCase := junitTestCase{ClassName: "spelling", Name: e.Path}
+		if words := results[e.Path]; len(words) > 0 {
+			// The typos found before the error.
+			testCase.Failure = &junitFailure{Message: fmt.Sprintf("%d typos found", len(words)), Type: "spelling", Text: junitTypos(words)}
+			suite.Failures++
+		}
+		testCase.Error = &junitFailure{Message: e.Err.Error(), Type: "io"}
+		suite.Errors++
+		suite.Cases = append(suite.Cases, testCase)
+	}
+	suite.Tests = len(suite.Cases)
+
+	report := junitTestSuites{Name: "spellchecker", Tests: suite.Tests, Failures: suite.Failures, Errors: suite.Errors, Suites: []junitTestSuite{suite}}
+	return writeXML(writer, report)
+}
+
+// junitTypos lists typos in the text of a failure, one per line.
+func junitTypos(words []MisspelledWord) string {
+	lines := make([]string, len(words))
+	for i, m := range words {
+		lines[i] = fmt.Sprintf("Line %d, Col %d: %s", m.LineNumber, m.Column, typoMessage(m))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// checkstyleReport is the root of a Checkstyle XML report.
+type checkstyleReport struct {
+	XMLName xml.Name         `xml:"checkstyle"`
+	Version string           `xml:"version,attr"`
+	Files   []checkstyleFile `xml:"file"`
+}
+
+type checkstyleFile struct {
+	Name   string            `xml:"name,attr"`
+	Errors []checkstyleError `xml:"error"`
+	// Exception is the error that kept the file from being checked, if any.
+	Exception string `xml:"exception,omitempty"`
+}
+
+type checkstyleError struct {
+	Line     int    `xml:"line,attr"`
+	Column   int    `xml:"column,attr"`
+	Severity string `xml:"severity,attr"`
+	Message  string `xml:"message,attr"`
+	Source   string `xml:"source,attr"`
+}
+
+// generateCheckstyleReport writes a Checkstyle XML report with one <error> element per
+// typo, grouped by file. Typos fail the run, so they are reported with severity "error".
+// A file or directory that could not be checked has an <exception> element instead.
+func generateCheckstyleReport(writer io.Writer, results map[string][]MisspelledWord, summary ScanSummary) error {
+	report := checkstyleReport{Version: "4.3"}
+	exceptions := make(map[string]string)
+	paths := sortedFiles(results)
+	for _, e := range summary.Errors {
+		if message, ok := exceptions[e.Path]; ok {
+			exceptions[e.Path] = message + "\n" + e.Err.Error()
+			continue
+		}
+		if results[e.Path] == nil {
+			paths = append(paths, e.Path)
+		}
+		exceptions[e.Path] = e.Err.Error()
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		file := checkstyleFile{Name: path, Exception: exceptions[path]}
+		for _, m := range results[path] {
+			file.Errors = append(file.Errors, checkstyleError{
+				Line:     m.LineNumber,
+				Column:   m.Column,
+				Severity: "error",
+				Message:  typoMessage(m),
+				Source:   "spellchecker." + m.rule(),
+			})
+		}
+		report.Files = append(report.Files, file)
+	}
+	return writeXML(writer, report)
+}
+
+// writeXML writes v as an indented XML document with a declaration.
+func writeXML(writer io.Writer, v any) error {
+	if _, err := io.WriteString(writer, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(writer)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(v); err != nil {
+		return err
+	}
+	_, err := io.WriteString(writer, "\n")
+	return err
+}
@@ -0,0 +1,90 @@
+package spellchecker
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+func TestGenerateJUnitReport(t *testing.T) {
+	results := map[string][]MisspelledWord{
+		"b.txt": {
+			{Word: "wrld", LineNumber: 1, Column: 7, Suggestions: []string{"world"}},
+			{Word: "<tag>", LineNumber: 2, Column: 1},
+		},
+	}
+	summary := ScanSummary{FilesScanned: 2, Files: []string{"a.txt", "b.txt"}}
+
+	var buf bytes.Buffer
+	if err := generateJUnitReport(&buf, results, summary); err != nil {
+		t.Fatalf("generateJUnitReport failed: %v", err)
+	}
+	output := buf.String()
+	if !strings.HasPrefix(output, xml.Header) {
+		t.Errorf("JUnit report should start with an XML declaration:\n%s", output)
+	}
+
+	var got junitTestSuites
+	if err := xml.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("JUnit report is not valid XML: %v\n%s", err, output)
+	}
+	if got.Tests != 2 || got.Failures != 1 || len(got.Suites) != 1 {
+		t.Fatalf("Expected 2 tests with 1 failure in one suite, got %+v", got)
+	}
+	cases := got.Suites[0].Cases
+	if cases[0].Name != "a.txt" || cases[0].Failure != nil {
+		t.Errorf("A clean file should be a passing test case, got %+v", cases[0])
+	}
+	failure := cases[1].Failure
+	if cases[1].Name != "b.txt" || failure == nil {
+		t.Fatalf("A file with typos should be a failing test case, got %+v", cases[1])
+	}
+	wantText := "Line 1, Col 7: \"wrld\" appears to be a typo. Did you mean: world?\nLine 2, Col 1: \"<tag>\" appears to be a typo."
+	if failure.Message != "2 typos found" || failure.Text != wantText {
+		t.Errorf("Unexpected failure %q: %q", failure.Message, failure.Text)
+	}
+}
+
+func TestGenerateCheckstyleReport(t *testing.T) {
+	results := map[string][]MisspelledWord{
+		"docs/b.md": {{Word: "colour", LineNumber: 4, Column: 2, Rule: RuleForbiddenWord}},
+		"a.txt": {
+			{Word: "wrld", LineNumber: 1, Column: 7, Suggestions: []string{"world"}},
+			{Word: "helo", LineNumber: 3, Column: 1, Suggestions: []string{"hello", "help"}},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := generateCheckstyleReport(&buf, results, ScanSummary{}); err != nil {
+		t.Fatalf("generateCheckstyleReport failed: %v", err)
+	}
+
+	var got checkstyleReport
+	if err := xml.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("Checkstyle report is not valid XML: %v\n%s", err, buf.String())
+	}
+	if len(got.Files) != 2 || got.Files[0].Name != "a.txt" || got.Files[1].Name != "docs/b.md" {
+		t.Fatalf("Expected files sorted by path, got %+v", got.Files)
+	}
+	if len(got.Files[0].Errors) != 2 {
+		t.Fatalf("Expected one error per typo, got %+v", got.Files[0].Errors)
+	}
+	want := checkstyleError{Line: 1, Column: 7, Severity: "error", Message: `"wrld" appears to be a typo. Did you mean: world?`, Source: "spellchecker.unknown-word"}
+	if got.Files[0].Errors[0] != want {
+		t.Errorf("Unexpected error %+v; want %+v", got.Files[0].Errors[0], want)
+	}
+	if source := got.Files[1].Errors[0].Source; source != "spellchecker.forbidden-word" {
+		t.Errorf("Expected a forbidden-word source, got %q", source)
+	}
+}
+
+func TestGenerateCheckstyleReportNoTypos(t *testing.T) {
+	var buf bytes.Buffer
+	if err := generateCheckstyleReport(&buf, map[string][]MisspelledWord{}, ScanSummary{}); err != nil {
+		t.Fatalf("generateCheckstyleReport failed: %v", err)
+	}
+	if !strings.Contains(buf.String(), `<checkstyle version="4.3"></checkstyle>`) {
+		t.Errorf("Expected an empty checkstyle element, got:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,255 @@
+package spellchecker
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// formatExtensions maps an output file extension to the report format it implies.
+var formatExtensions = map[string]string{
+	".html":  "html",
+	".json":  "json",
+	".sarif": "sarif",
+}
+
+// Reporter writes the results of a check in one format. Results map each file with
+// typos to its typos, in the order they occur; summary describes the whole scan.
+type Reporter interface {
+	Report(w io.Writer, results map[string][]MisspelledWord, summary ScanSummary) error
+}
+
+// ReporterFunc adapts a function to the Reporter interface.
+type ReporterFunc func(w io.Writer, results map[string][]MisspelledWord, summary ScanSummary) error
+
+// Report calls f.
+func (f ReporterFunc) Report(w io.Writer, results map[string][]MisspelledWord, summary ScanSummary) error {
+	return f(w, results, summary)
+}
+
+var (
+	reportersMu sync.RWMutex
+	// reporters maps each format name to its reporter; see RegisterReporter.
+	reporters = map[string]Reporter{
+		"txt": ReporterFunc(func(w io.Writer, results map[string][]MisspelledWord, summary ScanSummary) error {
+			generateTextReport(w, results, summary)
+			return nil
+		}),
+		"html": ReporterFunc(func(w io.Writer, results map[string][]MisspelledWord, summary ScanSummary) error {
+			generateHTMLReport(w, results, summary)
+			return nil
+		}),
+		"json":       ReporterFunc(generateJSONReport),
+		"sarif":      ReporterFunc(generateSARIFReport),
+		"junit":      ReporterFunc(generateJUnitReport),
+		"checkstyle": ReporterFunc(generateCheckstyleReport),
+		"github":     ReporterFunc(generateGitHubReport),
+		"gitlab":     ReporterFunc(generateGitLabReport),
+	}
+	// reporterNames lists the format names in the order error messages show them.
+	reporterNames = []string{"txt", "html", "json", "sarif", "junit", "checkstyle", "github", "gitlab"}
+)
+
+// RegisterReporter makes reporter available under the format name, in lower case, to
+// NewReporter and ReportFormat. It replaces a reporter registered under that name,
+// including the built-in ones.
+func RegisterReporter(format string, reporter Reporter) {
+	format = strings.ToLower(format)
+	reportersMu.Lock()
+	defer reportersMu.Unlock()
+	if _, ok := reporters[format]; !ok {
+		reporterNames = append(reporterNames, format)
+	}
+	reporters[format] = reporter
+}
+
+// NewReporter returns the reporter of a format: txt, html, json, sarif, junit,
+// checkstyle, github, gitlab, or one added with RegisterReporter. The name is not
+// case-sensitive.
+func NewReporter(format string) (Reporter, error) {
+	reportersMu.RLock()
+	defer reportersMu.RUnlock()
+	if reporter, ok := reporters[strings.ToLower(format)]; ok {
+		return reporter, nil
+	}
+	names := reporterNames
+	return nil, fmt.Errorf("unknown report format %q (want %s or %s)", format, strings.Join(names[:len(names)-1], ", "), names[len(names)-1])
+}
+
+// ReportFormat returns the report format to write: format if it is set, otherwise the
+// format implied by the output file's extension, otherwise txt.
+func ReportFormat(format, output string) (string, error) {
+	format = strings.ToLower(format)
+	if format == "" {
+		if implied, ok := formatExtensions[strings.ToLower(filepath.Ext(output))]; ok {
+			return implied, nil
+		}
+		return "txt", nil
+	}
+	if _, err := NewReporter(format); err != nil {
+		return "", err
+	}
+	return format, nil
+}
+
+// --- Shared constants for reusable HTML parts ---
+const htmlHeader = `<!DOCTYPE html>
+<html lang="en"><head><meta charset="UTF-8"><title>Spell Check Report</title>
+<style>body{font-family:sans-serif;max-width:960px;margin:20px auto}h1,h2{border-bottom:2px solid #eee}table{width:100%;border-collapse:collapse}th,td{padding:12px;border:1px solid #ddd}th{background-color:#3498db;color:white}td:nth-child(3){font-weight:bold;color:#c0392b}td:nth-child(4){color:#27ae60}ul{list-style-type:none;padding:0}li{padding:8px;border-bottom:1px solid #eee}a{text-decoration:none;color:#3498db}</style>
+</head><body>`
+
+const htmlFooter = "</body></html>"
+
+// --- NEW: Multi-file HTML report generator ---
+
+// GenerateMultiFileHTMLReport creates a directory with an index.html and separate reports.
+// The index also lists the files and directories in summary.Errors.
+func GenerateMultiFileHTMLReport(outputDir string, results map[string][]MisspelledWord, summary ScanSummary) error {
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return fmt.Errorf("could not create output directory %s: %w", outputDir, err)
+	}
+
+	if err := generateIndexFile(outputDir, results, summary); err != nil {
+		return err
+	}
+
+	for file, words := range results {
+		if err := generateSingleReportFile(outputDir, file, words); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sanitizePath converts a file path into a safe filename for a report.
+func sanitizePath(path string) string {
+	replacer := strings.NewReplacer("/", "_", "\\", "_", ":", "_")
+	return replacer.Replace(path) + ".html"
+}
+
+// generateIndexFile creates the main summary/index page with links.
+func generateIndexFile(outputDir string, results map[string][]MisspelledWord, summary ScanSummary) error {
+	indexPath := filepath.Join(outputDir, "index.html")
+	file, err := os.Create(indexPath)
+	if err != nil {
+		return fmt.Errorf("could not create index file: %w", err)
+	}
+	defer file.Close()
+
+	fmt.Fprint(file, htmlHeader)
+	fmt.Fprint(file, "<h1>Spell Check Summary</h1>")
+
+	if len(results) == 0 {
+		fmt.Fprint(file, `<p>✅ No typos found.</p>`)
+	} else {
+		fmt.Fprint(file, "<ul>")
+		for path, words := range results {
+			reportFileName := sanitizePath(path)
+			fmt.Fprintf(file, `<li><a href="%s">%s</a> (%d typos)</li>`, reportFileName, path, len(words))
+		}
+		fmt.Fprint(file, "</ul>")
+	}
+	writeErrorsSection(file, summary.Errors)
+
+	fmt.Fprint(file, htmlFooter)
+	return nil
+}
+
+// generateSingleReportFile creates a detailed HTML report for one source file.
+func generateSingleReportFile(outputDir, filePath string, words []MisspelledWord) error {
+	reportFileName := sanitizePath(filePath)
+	reportPath := filepath.Join(outputDir, reportFileName)
+	file, err := os.Create(reportPath)
+	if err != nil {
+		return fmt.Errorf("could not create report file for %s: %w", filePath, err)
+	}
+	defer file.Close()
+
+	fmt.Fprint(file, htmlHeader)
+	writeFileReportTable(file, filePath, words)
+	fmt.Fprint(file, htmlFooter)
+	return nil
+}
+
+// --- REFACTORED: Original functions now use helpers ---
+
+// generateHTMLReport generates a single, self-contained HTML report.
+func generateHTMLReport(writer io.Writer, results map[string][]MisspelledWord, summary ScanSummary) {
+	fmt.Fprint(writer, htmlHeader)
+	fmt.Fprint(writer, "<h1>Spell Check Report</h1>")
+
+	if len(results) == 0 {
+		fmt.Fprint(writer, `<p>✅ No typos found.</p>`)
+	} else {
+		for file, words := range results {
+			writeFileReportTable(writer, file, words)
+		}
+	}
+	writeErrorsSection(writer, summary.Errors)
+	fmt.Fprint(writer, htmlFooter)
+}
+
+// writeErrorsSection writes the H2 and list of the files that could not be checked, if any.
+func writeErrorsSection(writer io.Writer, fileErrors []FileError) {
+	if len(fileErrors) == 0 {
+		return
+	}
+	fmt.Fprint(writer, "<h2>Errors</h2><ul>")
+	for _, e := range fileErrors {
+		fmt.Fprintf(writer, "<li>%s</li>", html.EscapeString(e.Error()))
+	}
+	fmt.Fprint(writer, "</ul>")
+}
+
+// writeFileReportTable is a shared helper that writes the H2 and table for a file's results.
+func writeFileReportTable(writer io.Writer, file string, words []MisspelledWord) {
+	fmt.Fprintf(writer, `<h2>Typos in: %s</h2>`, filepath.Base(file))
+	fmt.Fprint(writer, `<table><tr><th>Line</th><th>Column</th><th>Word</th><th>Suggestions</th></tr>`)
+	for _, m := range words {
+		suggestionsStr := strings.Join(m.Suggestions, ", ")
+		fmt.Fprintf(writer, "<tr><td>%d</td><td>%d</td><td>%s</td><td>%s</td></tr>", m.LineNumber, m.Column, m.Word, suggestionsStr)
+	}
+	fmt.Fprint(writer, `</table>`)
+}
+
+// --- Text report generator remains unchanged ---
+func generateTextReport(writer io.Writer, results map[string][]MisspelledWord, summary ScanSummary) {
+	if len(results) == 0 {
+		fmt.Fprintln(writer, "No typos found.")
+	} else {
+		fmt.Fprintln(writer, "Typos found:")
+		for file, words := range results {
+			fmt.Fprintf(writer, "\n--- In file %s ---\n", file)
+			for _, m := range words {
+				fmt.Fprintf(writer, "- Line %d, Col %d: %s\n", m.LineNumber, m.Column, typoMessage(m))
+			}
+		}
+	}
+	if len(summary.Errors) > 0 {
+		fmt.Fprintln(writer, "\n--- Errors ---")
+		for _, e := range summary.Errors {
+			fmt.Fprintf(writer, "- %v\n", e)
+		}
+	}
+}
+
+// typoMessage describes a typo in one sentence, the way the text report does.
+func typoMessage(m MisspelledWord) string {
+	var message string
+	switch m.rule() {
+	case RuleForbiddenWord:
+		message = fmt.Sprintf("\"%s\" is a forbidden word.", m.Word)
+	case RuleUnusedDirective:
+		message = unusedDirectiveMessage(m.Word)
+	default:
+		message = fmt.Sprintf("\"%s\" appears to be a typo.", m.Word)
+	}
+	if len(m.Suggestions) > 0 {
+		message += " Did you mean: " + strings.Join(m.Suggestions, ", ") + "?"
+	}
+	return message
+}
@@ -0,0 +1,145 @@
+package spellchecker
+
+import (
+	"bytes"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// IMPROVED: Assertions are more specific.
+func TestGenerateTextReport(t *testing.T) {
+	results := map[string][]MisspelledWord{
+		"test.txt": {
+			{Word: "errror", LineNumber: 1, Column: 5, Suggestions: []string{"error"}},
+		},
+	}
+
+	var buf bytes.Buffer
+	generateTextReport(&buf, results, ScanSummary{})
+	output := buf.String()
+
+	// Check for the exact, complete output line.
+	expectedLine := `- Line 1, Col 5: "errror" appears to be a typo. Did you mean: error?`
+	if !strings.Contains(output, expectedLine) {
+		t.Errorf("Text report missing expected line.\nGOT:\n%s\nWANT (to contain):\n%s", output, expectedLine)
+	}
+}
+
+// IMPROVED: Check for the new "Suggestions" table header.
+func TestGenerateHTMLReport(t *testing.T) {
+	results := map[string][]MisspelledWord{
+		"test.txt": {
+			{Word: "wrod", LineNumber: 2, Column: 10, Suggestions: []string{"world"}},
+		},
+	}
+
+	var buf bytes.Buffer
+	generateHTMLReport(&buf, results, ScanSummary{})
+	output := buf.String()
+
+	if !strings.Contains(output, "<th>Suggestions</th>") {
+		t.Error("HTML report missing 'Suggestions' table header")
+	}
+	if !strings.Contains(output, "<td>world</td>") {
+		t.Error("HTML report missing suggestion in a <td> tag")
+	}
+}
+
+func TestGenerateReportNoTypos(t *testing.T) {
+	results := make(map[string][]MisspelledWord)
+	var textBuf bytes.Buffer
+	generateTextReport(&textBuf, results, ScanSummary{})
+
+	if !strings.Contains(textBuf.String(), "No typos found.") {
+		t.Error("Text report for no typos is incorrect")
+	}
+
+	var htmlBuf bytes.Buffer
+	generateHTMLReport(&htmlBuf, results, ScanSummary{})
+	if !strings.Contains(htmlBuf.String(), "No typos found.") {
+		t.Error("HTML report for no typos is incorrect")
+	}
+}
+
+func TestReportsIncludeErrors(t *testing.T) {
+	summary := ScanSummary{Errors: []FileError{{Path: "secret.txt", Err: fs.ErrPermission}}}
+	want := map[string]string{
+		"txt":  "\n--- Errors ---\n- secret.txt: permission denied\n",
+		"html": "<h2>Errors</h2><ul><li>secret.txt: permission denied</li></ul>",
+		"json": `"errors": [
+    {
+      "path": "secret.txt",
+      "message": "permission denied"
+    }
+  ]`,
+		"sarif": `"executionSuccessful": false`,
+		"junit": `<error message="permission denied" type="io"></error>`,
+		"checkstyle": `<file name="secret.txt">
+    <exception>permission denied</exception>`,
+		"github": "::error file=secret.txt,title=Could not check file::permission denied\n",
+		"gitlab": `"check_name": "file-error"`,
+	}
+	for format, wantOutput := range want {
+		reporter, err := NewReporter(format)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var buf bytes.Buffer
+		if err := reporter.Report(&buf, map[string][]MisspelledWord{}, summary); err != nil {
+			t.Fatalf("%s report failed: %v", format, err)
+		}
+		if !strings.Contains(buf.String(), wantOutput) {
+			t.Errorf("%s report is missing the error.\nGOT:\n%s\nWANT (to contain):\n%s", format, buf.String(), wantOutput)
+		}
+	}
+}
+
+func TestGenerateMultiFileHTMLReportIncludesErrors(t *testing.T) {
+	outputDir := t.TempDir()
+	results := map[string][]MisspelledWord{
+		"test.txt": {{Word: "wrod", LineNumber: 2, Column: 10, Suggestions: []string{"world"}}},
+	}
+	summary := ScanSummary{Errors: []FileError{{Path: "secret.txt", Err: fs.ErrPermission}}}
+	if err := GenerateMultiFileHTMLReport(outputDir, results, summary); err != nil {
+		t.Fatalf("GenerateMultiFileHTMLReport failed: %v", err)
+	}
+
+	index, err := os.ReadFile(filepath.Join(outputDir, "index.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "<h2>Errors</h2><ul><li>secret.txt: permission denied</li></ul>"
+	if !strings.Contains(string(index), want) {
+		t.Errorf("index.html is missing the error.\nGOT:\n%s\nWANT (to contain):\n%s", index, want)
+	}
+}
+
+func TestReportFormat(t *testing.T) {
+	testCases := []struct {
+		format, output string
+		want           string
+		wantErr        bool
+	}{
+		{"", "", "txt", false},
+		{"", "report.txt", "txt", false},
+		{"", "report.html", "html", false},
+		{"", "report.JSON", "json", false},
+		{"json", "report.txt", "json", false},
+		{"HTML", "", "html", false},
+		{"junit", "report.xml", "junit", false},
+		{"checkstyle", "", "checkstyle", false},
+		{"GitHub", "", "github", false},
+		{"gitlab", "gl-code-quality-report.json", "gitlab", false},
+		{"xml", "", "", true},
+	}
+
+	for _, tc := range testCases {
+		got, err := ReportFormat(tc.format, tc.output)
+		if (err != nil) != tc.wantErr || got != tc.want {
+			t.Errorf("ReportFormat(%q, %q) = %q, %v; want %q (error: %v)", tc.format, tc.output, got, err, tc.want, tc.wantErr)
+		}
+	}
+}
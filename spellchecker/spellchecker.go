@@ -0,0 +1,98 @@
+// Package spellchecker checks the spelling of plain text, Markdown prose and the
+// comments and string literals of source code against a dictionary. It is the library
+// behind the spellchecker command, which adds flags, configuration files and the
+// interactive fix command:
+//
+//	dictionary, err := spellchecker.LoadDictionary("")
+//	if err != nil {
+//		return err
+//	}
+//	dictionary.BuildIndex()
+//	checker := spellchecker.NewChecker(dictionary, spellchecker.CheckOptions{})
+//	results, summary, err := checker.CheckPath(ctx, "docs")
+//	if err != nil {
+//		return err
+//	}
+//	reporter, _ := spellchecker.NewReporter("json")
+//	return reporter.Report(os.Stdout, results, summary)
+package spellchecker
+
+import (
+	"context"
+	"io"
+	"os"
+)
+
+// Checker checks files and documents against a dictionary, with fixed options. It is
+// safe for concurrent use as long as the dictionary is not modified.
+type Checker struct {
+	dictionary *Dictionary
+	opts       CheckOptions
+}
+
+// NewChecker returns a checker of dictionary with opts. Suggestions use the indexes of
+// the dictionary, which must be built first; see Dictionary.BuildIndex and
+// Dictionary.BuildPhoneticIndex.
+func NewChecker(dictionary *Dictionary, opts CheckOptions) *Checker {
+	return &Checker{dictionary: dictionary, opts: opts}
+}
+
+// CheckReader checks a document read from r, such as standard input or generated
+// text, as if it were the file name: the extension of name selects how the document
+// is checked, e.g. as Markdown, and the result is reported under it.
+func (c *Checker) CheckReader(ctx context.Context, name string, r io.Reader) (CheckResult, error) {
+	if err := ctx.Err(); err != nil {
+		return CheckResult{}, err
+	}
+	lines, err := scanLines(r)
+	if err != nil {
+		return CheckResult{}, err
+	}
+	if err := ctx.Err(); err != nil {
+		return CheckResult{}, err
+	}
+	return CheckResult{FilePath: name, Typos: checkLines(name, lines, c.dictionary, c.opts)}, nil
+}
+
+// CheckPath checks a file, or the files of a directory that the options select. It
+// returns the typos of each file that has any, keyed by path, and a summary of the
+// scan. Path itself must exist, but files and directories below it that cannot be
+// read do not fail the check: they are listed in the summary's Errors. When ctx is
+// done before the check finishes, its error is returned with the results so far.
+func (c *Checker) CheckPath(ctx context.Context, path string) (map[string][]MisspelledWord, ScanSummary, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, ScanSummary{}, err
+	}
+	if _, err := os.Stat(path); err != nil {
+		return nil, ScanSummary{}, err
+	}
+	return runConcurrentChecker(ctx, path, c.dictionary, c.opts)
+}
+
+// Watch checks the files of path again as they change, after a first CheckPath
+// returned results and summary, and calls report with the results of all the files
+// after every change, until ctx is done. It writes what it watches, and when it
+// checks, to the Log of the options.
+func (c *Checker) Watch(ctx context.Context, path string, results map[string][]MisspelledWord, summary ScanSummary, report func(map[string][]MisspelledWord, ScanSummary) error) error {
+	return runWatch(ctx, path, c.dictionary, c.opts, results, summary, report)
+}
+
+// ServeLSP serves the Language Server Protocol on in and out, such as standard input
+// and output, until the client exits. Words are added to the personal dictionary file
+// personal, if set, and to the checker's dictionary.
+func (c *Checker) ServeLSP(in io.Reader, out io.Writer, personal string) error {
+	return runLSP(in, out, c.dictionary, c.opts, personal)
+}
+
+// Validate returns an error for malformed options, such as an invalid glob pattern,
+// which would otherwise silently match nothing, or an unknown distance metric.
+func (o CheckOptions) Validate() error {
+	if err := validatePatterns("exclude", o.Exclude); err != nil {
+		return err
+	}
+	if err := validatePatterns("include", o.Include); err != nil {
+		return err
+	}
+	_, err := distanceFunc(o.Suggestions.Metric)
+	return err
+}
@@ -0,0 +1,112 @@
+package spellchecker_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"spell-checker-cli/spellchecker"
+)
+
+func testDictionary() *spellchecker.Dictionary {
+	d := &spellchecker.Dictionary{Words: map[string]struct{}{"fix": {}, "the": {}, "parser": {}, "hello": {}, "world": {}}}
+	d.BuildIndex()
+	return d
+}
+
+func TestCheckerCheckReader(t *testing.T) {
+	checker := spellchecker.NewChecker(testDictionary(), spellchecker.CheckOptions{})
+	// The name selects Markdown extraction, so the code span is not checked.
+	input := strings.NewReader("Fix teh parser\n\n`qwzx`\n")
+	got, err := checker.CheckReader(context.Background(), "COMMIT_EDITMSG.md", input)
+	if err != nil {
+		t.Fatalf("CheckReader() error = %v", err)
+	}
+	want := spellchecker.CheckResult{FilePath: "COMMIT_EDITMSG.md", Typos: []spellchecker.MisspelledWord{
+		{Word: "teh", LineNumber: 1, Column: 5, Suggestions: []string{"the"}},
+	}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("CheckReader() = %+v, want %+v", got, want)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := checker.CheckReader(ctx, "notes.txt", strings.NewReader("teh")); err != context.Canceled {
+		t.Errorf("CheckReader() with a canceled context error = %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestCheckerCheckPath(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "a.txt"), []byte("hello wrld"), 0644)
+	os.WriteFile(filepath.Join(dir, "b.log"), []byte("hello wrld"), 0644)
+
+	checker := spellchecker.NewChecker(testDictionary(), spellchecker.CheckOptions{Exclude: []string{"*.log"}})
+	results, summary, err := checker.CheckPath(context.Background(), dir)
+	if err != nil {
+		t.Fatalf("CheckPath() error = %v", err)
+	}
+	path := filepath.Join(dir, "a.txt")
+	want := map[string][]spellchecker.MisspelledWord{path: {{Word: "wrld", LineNumber: 1, Column: 7, Suggestions: []string{"world"}}}}
+	if !reflect.DeepEqual(results, want) {
+		t.Errorf("CheckPath() = %+v, want %+v", results, want)
+	}
+	if summary.FilesScanned != 1 || summary.FilesSkipped != 1 {
+		t.Errorf("summary = %+v, want 1 file scanned and 1 skipped", summary)
+	}
+
+	// A mistyped path fails the check rather than passing with nothing checked.
+	if _, _, err := checker.CheckPath(context.Background(), filepath.Join(dir, "nosuch")); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("CheckPath() of a missing path error = %v, want %v", err, fs.ErrNotExist)
+	}
+}
+
+func TestCheckOptionsValidate(t *testing.T) {
+	testCases := []struct {
+		name    string
+		opts    spellchecker.CheckOptions
+		wantErr bool
+	}{
+		{"defaults", spellchecker.CheckOptions{}, false},
+		{"valid", spellchecker.CheckOptions{Exclude: []string{"*.log"}, Include: []string{"docs/**"}, Suggestions: spellchecker.SuggestionOptions{Metric: spellchecker.MetricLevenshtein}}, false},
+		{"bad exclude", spellchecker.CheckOptions{Exclude: []string{"[a-"}}, true},
+		{"bad include", spellchecker.CheckOptions{Include: []string{"docs/[a-"}}, true},
+		{"bad metric", spellchecker.CheckOptions{Suggestions: spellchecker.SuggestionOptions{Metric: "hamming"}}, true},
+	}
+	for _, tc := range testCases {
+		if err := tc.opts.Validate(); (err != nil) != tc.wantErr {
+			t.Errorf("%s: Validate() error = %v, want error %v", tc.name, err, tc.wantErr)
+		}
+	}
+}
+
+func TestRegisterReporter(t *testing.T) {
+	spellchecker.RegisterReporter("Count", spellchecker.ReporterFunc(func(w io.Writer, results map[string][]spellchecker.MisspelledWord, summary spellchecker.ScanSummary) error {
+		_, err := io.WriteString(w, strings.Repeat("x", len(results)))
+		return err
+	}))
+	format, err := spellchecker.ReportFormat("COUNT", "")
+	if err != nil || format != "count" {
+		t.Fatalf("ReportFormat() = %q, %v; want the registered format", format, err)
+	}
+	reporter, err := spellchecker.NewReporter(format)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	results := map[string][]spellchecker.MisspelledWord{"a.txt": {{Word: "teh"}}, "b.txt": {{Word: "wrld"}}}
+	if err := reporter.Report(&buf, results, spellchecker.ScanSummary{}); err != nil || buf.String() != "xx" {
+		t.Errorf("Report() = %q, %v; want %q", buf.String(), err, "xx")
+	}
+
+	if _, err := spellchecker.NewReporter("yaml"); err == nil || !strings.Contains(err.Error(), "gitlab or count") {
+		t.Errorf("NewReporter(yaml) error = %v, want an error listing the registered format", err)
+	}
+}
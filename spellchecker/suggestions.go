@@ -0,0 +1,321 @@
+package spellchecker
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"unicode/utf8"
+)
+
+// levenshteinThreshold is the maximum edit distance to be considered a suggestion.
+const levenshteinThreshold = 2
+
+// Edit distance metrics for SuggestionOptions.Metric.
+const (
+	// MetricDamerau counts a swap of two adjacent letters as one edit (optimal string alignment).
+	MetricDamerau = "damerau"
+	// MetricLevenshtein counts insertions, deletions and substitutions only.
+	MetricLevenshtein = "levenshtein"
+)
+
+// SuggestionOptions controls how suggestions are generated for a misspelled word.
+type SuggestionOptions struct {
+	// Max is the number of suggestions returned per word; 0 returns all of them.
+	Max int
+	// Metric is the edit distance used to find and rank suggestions; "" means MetricDamerau.
+	Metric string
+	// Phonetic also suggests words that sound like the misspelled word (same Double
+	// Metaphone key), however many edits away they are.
+	Phonetic bool
+}
+
+// distanceFunc returns the edit distance function for a metric name.
+func distanceFunc(metric string) (func(a, b string) int, error) {
+	switch metric {
+	case "", MetricDamerau:
+		return osaDistance, nil
+	case MetricLevenshtein:
+		return levenshteinDistance, nil
+	}
+	return nil, fmt.Errorf("unknown distance metric %q (want %s or %s)", metric, MetricDamerau, MetricLevenshtein)
+}
+
+// suggestionCandidate is a dictionary word close to a misspelled word, together with
+// the features used to rank it.
+type suggestionCandidate struct {
+	Word      string
+	Distance  int
+	Swapped   bool
+	Phonetic  bool
+	Frequency int
+	Keyboard  int
+	Prefix    int
+}
+
+// generateSuggestions finds words in the dictionary that are "close" to a misspelled word
+// and returns them best first (see rankSuggestions), capped at opts.Max.
+// Words marked NoSuggest are skipped. The dictionary's indexes are used when they have
+// been built; otherwise every dictionary word is compared.
+func generateSuggestions(word string, dictionary *Dictionary, opts SuggestionOptions) []string {
+	var candidates []suggestionCandidate
+	lowerWord := normalizeWord(word)
+	distance, err := distanceFunc(opts.Metric)
+	if err != nil {
+		// Options are validated when the configuration is loaded.
+		distance = osaDistance
+	}
+	transpositions := opts.Metric != MetricLevenshtein
+
+	if dictionary.index != nil {
+		dictionary.index.search(lowerWord, levenshteinThreshold, transpositions, func(match string, distance int) {
+			candidates = append(candidates, suggestionCandidate{Word: match, Distance: distance})
+		})
+	} else {
+		wordLen := utf8.RuneCountInString(lowerWord)
+		for dictWord := range dictionary.Words {
+			if _, skip := dictionary.NoSuggest[dictWord]; skip {
+				continue
+			}
+			// Optimization: skip comparing words with a length difference greater than the threshold.
+			if math.Abs(float64(utf8.RuneCountInString(dictWord)-wordLen)) > float64(levenshteinThreshold) {
+				continue
+			}
+
+			if d := distance(lowerWord, dictWord); d <= levenshteinThreshold {
+				candidates = append(candidates, suggestionCandidate{Word: dictWord, Distance: d})
+			}
+		}
+	}
+
+	if opts.Phonetic {
+		candidates = mergePhoneticCandidates(lowerWord, candidates, dictionary, distance)
+	}
+	return rankSuggestions(lowerWord, candidates, dictionary, opts.Max)
+}
+
+// mergePhoneticCandidates marks the candidates that sound like word and adds the
+// sound-alike words that are too many edits away to have been found already. Their
+// real edit distance is kept, so they rank after closer spellings.
+func mergePhoneticCandidates(word string, candidates []suggestionCandidate, dictionary *Dictionary, distance func(a, b string) int) []suggestionCandidate {
+	soundAlikes := dictionary.phoneticWords().lookup(word)
+	if len(soundAlikes) == 0 {
+		return candidates
+	}
+
+	found := make(map[string]int, len(candidates))
+	for i, c := range candidates {
+		found[c.Word] = i
+	}
+	for _, match := range soundAlikes {
+		if i, ok := found[match]; ok {
+			candidates[i].Phonetic = true
+			continue
+		}
+		if match == word {
+			continue
+		}
+		candidates = append(candidates, suggestionCandidate{Word: match, Distance: distance(word, match), Phonetic: true})
+	}
+	return candidates
+}
+
+// rankSuggestions orders candidates by edit distance, preferring at equal distance the
+// words that differ only by swapped adjacent letters, then the words that sound alike
+// (when phonetic suggestions are on), then by word frequency, then by
+// how many substituted letters sit next to each other on a QWERTY keyboard, then by
+// the length of the prefix shared with the misspelled word, and finally alphabetically,
+// so that the same input always gives the same list. At most limit words are returned
+// unless limit is 0.
+func rankSuggestions(word string, candidates []suggestionCandidate, dictionary *Dictionary, limit int) []string {
+	for i := range candidates {
+		c := &candidates[i]
+		c.Swapped = onlyTransposed(word, c.Word)
+		c.Frequency = dictionary.Frequency[c.Word]
+		c.Keyboard = keyboardProximity(word, c.Word)
+		c.Prefix = sharedPrefixLength(word, c.Word)
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		switch {
+		case a.Distance != b.Distance:
+			return a.Distance < b.Distance
+		case a.Swapped != b.Swapped:
+			return a.Swapped
+		case a.Phonetic != b.Phonetic:
+			return a.Phonetic
+		case a.Frequency != b.Frequency:
+			return a.Frequency > b.Frequency
+		case a.Keyboard != b.Keyboard:
+			return a.Keyboard > b.Keyboard
+		case a.Prefix != b.Prefix:
+			return a.Prefix > b.Prefix
+		}
+		return a.Word < b.Word
+	})
+
+	if limit > 0 && len(candidates) > limit {
+		candidates = candidates[:limit]
+	}
+	suggestions := make([]string, len(candidates))
+	for i, c := range candidates {
+		suggestions[i] = c.Word
+	}
+	return suggestions
+}
+
+// qwertyRows is the letter layout of a QWERTY keyboard, used to judge which typos are
+// likely slips of the finger.
+var qwertyRows = []string{"qwertyuiop", "asdfghjkl", "zxcvbnm"}
+
+// qwertyOffsets is the horizontal stagger of each row, in key widths.
+var qwertyOffsets = []float64{0, 0.25, 0.75}
+
+type keyPosition struct {
+	Row int
+	X   float64
+}
+
+var qwertyKeys = func() map[rune]keyPosition {
+	keys := make(map[rune]keyPosition)
+	for row, letters := range qwertyRows {
+		for col, r := range letters {
+			keys[r] = keyPosition{Row: row, X: float64(col) + qwertyOffsets[row]}
+		}
+	}
+	return keys
+}()
+
+// keysAdjacent reports whether a and b are neighbouring keys on a QWERTY keyboard.
+func keysAdjacent(a, b rune) bool {
+	ka, okA := qwertyKeys[a]
+	kb, okB := qwertyKeys[b]
+	if !okA || !okB || a == b {
+		return false
+	}
+	dx := math.Abs(ka.X - kb.X)
+	switch math.Abs(float64(ka.Row - kb.Row)) {
+	case 0:
+		return dx <= 1
+	case 1:
+		return dx < 1
+	}
+	return false
+}
+
+// keyboardProximity counts the substituted letters between two words of equal length
+// that are neighbouring keys, e.g. 1 for "wprld" and "world".
+func keyboardProximity(a, b string) int {
+	runesA, runesB := []rune(a), []rune(b)
+	if len(runesA) != len(runesB) {
+		return 0
+	}
+	count := 0
+	for i := range runesA {
+		if keysAdjacent(runesA[i], runesB[i]) {
+			count++
+		}
+	}
+	return count
+}
+
+// onlyTransposed reports whether a and b differ, and only by swaps of adjacent letters,
+// as "teh" and "the" do.
+func onlyTransposed(a, b string) bool {
+	runesA, runesB := []rune(a), []rune(b)
+	if len(runesA) != len(runesB) || a == b {
+		return false
+	}
+	for i := 0; i < len(runesA); i++ {
+		if runesA[i] == runesB[i] {
+			continue
+		}
+		if i+1 == len(runesA) || runesA[i] != runesB[i+1] || runesA[i+1] != runesB[i] {
+			return false
+		}
+		i++
+	}
+	return true
+}
+
+// sharedPrefixLength returns the number of leading runes a and b have in common.
+func sharedPrefixLength(a, b string) int {
+	runesA, runesB := []rune(a), []rune(b)
+	n := 0
+	for n < len(runesA) && n < len(runesB) && runesA[n] == runesB[n] {
+		n++
+	}
+	return n
+}
+
+// levenshteinDistance calculates the edit distance between two strings using dynamic programming.
+// Only two rows of the table are kept: without an index, it runs for every dictionary word.
+func levenshteinDistance(a, b string) int {
+	runesA := []rune(a)
+	runesB := []rune(b)
+	lenA, lenB := len(runesA), len(runesB)
+
+	prev := make([]int, lenB+1)
+	curr := make([]int, lenB+1)
+	for j := 0; j <= lenB; j++ {
+		prev[j] = j
+	}
+
+	for i := 1; i <= lenA; i++ {
+		curr[0] = i
+		for j := 1; j <= lenB; j++ {
+			cost := 0
+			if runesA[i-1] != runesB[j-1] {
+				cost = 1
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[lenB]
+}
+
+// osaDistance calculates the optimal string alignment distance: the Levenshtein distance
+// where swapping two adjacent letters also counts as a single edit, so "teh" is one edit
+// from "the" instead of two. Unlike full Damerau-Levenshtein, a substring is never
+// edited again after being transposed.
+func osaDistance(a, b string) int {
+	runesA := []rune(a)
+	runesB := []rune(b)
+	lenA, lenB := len(runesA), len(runesB)
+
+	// Three rows: the transposition step looks two rows back.
+	prevPrev := make([]int, lenB+1)
+	prev := make([]int, lenB+1)
+	curr := make([]int, lenB+1)
+	for j := 0; j <= lenB; j++ {
+		prev[j] = j
+	}
+
+	for i := 1; i <= lenA; i++ {
+		curr[0] = i
+		for j := 1; j <= lenB; j++ {
+			cost := 0
+			if runesA[i-1] != runesB[j-1] {
+				cost = 1
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && runesA[i-1] == runesB[j-2] && runesA[i-2] == runesB[j-1] && prevPrev[j-2]+1 < curr[j] {
+				curr[j] = prevPrev[j-2] + 1
+			}
+		}
+		prevPrev, prev, curr = prev, curr, prevPrev
+	}
+	return prev[lenB]
+}
+
+// min is a helper to find the minimum of three integers.
+func min(a, b, c int) int {
+	if a < b {
+		if a < c {
+			return a
+		}
+	} else if b < c {
+		return b
+	}
+	return c
+}
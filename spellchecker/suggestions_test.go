@@ -0,0 +1,185 @@
+package spellchecker
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestLevenshteinDistance(t *testing.T) {
+	testCases := []struct {
+		a, b     string
+		expected int
+	}{
+		{"", "hello", 5},
+		{"hello", "hello", 0},
+		{"cat", "car", 1},
+		{"apple", "aple", 1},
+		{"kitten", "sitting", 3},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.a+"->"+tc.b, func(t *testing.T) {
+			if got := levenshteinDistance(tc.a, tc.b); got != tc.expected {
+				t.Errorf("levenshteinDistance(%q, %q) = %d; want %d", tc.a, tc.b, got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestOSADistance(t *testing.T) {
+	testCases := []struct {
+		a, b     string
+		expected int
+	}{
+		{"", "hello", 5},
+		{"hello", "hello", 0},
+		{"cat", "car", 1},
+		{"teh", "the", 1},
+		{"recieve", "receive", 1},
+		{"form", "from", 1},
+		{"abcd", "badc", 2},
+		{"kitten", "sitting", 3},
+		// Optimal string alignment does not edit a transposed pair again.
+		{"ca", "abc", 3},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.a+"->"+tc.b, func(t *testing.T) {
+			if got := osaDistance(tc.a, tc.b); got != tc.expected {
+				t.Errorf("osaDistance(%q, %q) = %d; want %d", tc.a, tc.b, got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestGenerateSuggestionsTranspositions(t *testing.T) {
+	dictionary := &Dictionary{Words: map[string]struct{}{
+		"the": {}, "tea": {}, "ten": {}, "receive": {}, "relieve": {}, "from": {}, "form": {}, "foam": {},
+	}}
+
+	testCases := []struct {
+		word   string
+		metric string
+		want   []string
+	}{
+		// A swap is a single edit, and swapped-letter words rank first among equally close ones.
+		{"teh", MetricDamerau, []string{"the", "ten", "tea"}},
+		{"recieve", MetricDamerau, []string{"receive", "relieve"}},
+		{"fomr", MetricDamerau, []string{"form", "foam", "from"}},
+		// Without transpositions, a swap costs 2 edits and falls behind single substitutions.
+		{"teh", MetricLevenshtein, []string{"ten", "tea", "the"}},
+		{"recieve", MetricLevenshtein, []string{"relieve", "receive"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.metric+"/"+tc.word, func(t *testing.T) {
+			got := generateSuggestions(tc.word, dictionary, SuggestionOptions{Metric: tc.metric})
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("generateSuggestions(%q) = %v; want %v", tc.word, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestDistanceFunc(t *testing.T) {
+	if _, err := distanceFunc("hamming"); err == nil {
+		t.Error("Expected an error for an unknown metric, but got nil")
+	}
+	for _, metric := range []string{"", MetricDamerau, MetricLevenshtein} {
+		if _, err := distanceFunc(metric); err != nil {
+			t.Errorf("distanceFunc(%q) returned error %v", metric, err)
+		}
+	}
+}
+
+func TestGenerateSuggestions(t *testing.T) {
+	mockDictionary := &Dictionary{Words: map[string]struct{}{
+		"hello": {}, "world": {}, "error": {}, "errors": {}, "go": {}, "golang": {},
+		"state-of-the-art": {}, // Added for hyphenation test
+	}}
+
+	testCases := []struct {
+		word     string
+		expected []string
+	}{
+		{"wrold", []string{"world"}},
+		{"eror", []string{"error", "errors"}},
+		{"errror", []string{"error", "errors"}},
+		{"golan", []string{"golang"}},
+		{"xyz", []string{}},
+		// NEW: Test case for a misspelled hyphenated word.
+		{"state-of-the-artt", []string{"state-of-the-art"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.word, func(t *testing.T) {
+			got := generateSuggestions(tc.word, mockDictionary, SuggestionOptions{})
+			sort.Strings(got)
+			sort.Strings(tc.expected)
+			if !reflect.DeepEqual(got, tc.expected) {
+				t.Errorf("generateSuggestions(%q) = %v; want %v", tc.word, got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestGenerateSuggestionsRanking(t *testing.T) {
+	dictionary := &Dictionary{
+		Words: map[string]struct{}{
+			"world": {}, "word": {}, "would": {}, "wold": {}, "sword": {}, "words": {}, "cord": {}, "lord": {},
+		},
+		Frequency: map[string]int{"would": 10, "word": 3},
+	}
+
+	testCases := []struct {
+		name string
+		word string
+		max  int
+		want []string
+	}{
+		// Distance first: "wrld" is 1 edit from "wold" and "world", 2 from "would" and "word".
+		// "would" is more frequent than "word"; ties at distance 1 fall back to alphabetical order.
+		{"distance then frequency", "wrld", 0, []string{"wold", "world", "would", "word"}},
+		// "word" and "words" are one edit away and "word" is more frequent. At distance 2,
+		// "wold" beats "world" because 's' and 'd' are neighbouring keys.
+		{"capped list", "wors", 3, []string{"word", "words", "wold"}},
+		// "word", "cord" and "lord" are one substitution away; "word" is more frequent.
+		// At distance 2, "wold" comes first because 'e' is next to 'w' on the keyboard.
+		{"keyboard proximity", "eord", 0, []string{"word", "cord", "lord", "wold", "sword", "words", "world"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := generateSuggestions(tc.word, dictionary, SuggestionOptions{Max: tc.max})
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("generateSuggestions(%q) = %v; want %v", tc.word, got, tc.want)
+			}
+			// The indexed path must rank identically.
+			dictionary.BuildIndex()
+			defer func() { dictionary.index = nil }()
+			if indexed := generateSuggestions(tc.word, dictionary, SuggestionOptions{Max: tc.max}); !reflect.DeepEqual(indexed, tc.want) {
+				t.Errorf("indexed generateSuggestions(%q) = %v; want %v", tc.word, indexed, tc.want)
+			}
+		})
+	}
+}
+
+func TestKeyboardProximity(t *testing.T) {
+	testCases := []struct {
+		a, b string
+		want int
+	}{
+		{"wprld", "world", 1},
+		{"qorld", "world", 1},
+		{"zorld", "world", 0},
+		{"teh", "the", 0},
+		{"hellp", "hello", 1},
+		{"abc", "abcd", 0},
+	}
+	for _, tc := range testCases {
+		if got := keyboardProximity(tc.a, tc.b); got != tc.want {
+			t.Errorf("keyboardProximity(%q, %q) = %d; want %d", tc.a, tc.b, got, tc.want)
+		}
+	}
+}
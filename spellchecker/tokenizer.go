@@ -0,0 +1,136 @@
+package spellchecker
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+// token is a single word found on a line, with its 1-based column counted in runes.
+type token struct {
+	Text   string
+	Column int
+}
+
+// noSpaceScripts are scripts that do not separate words with spaces. Segmenting
+// them needs a dictionary-driven algorithm, so their runs are skipped instead of
+// being reported one character at a time.
+var noSpaceScripts = []*unicode.RangeTable{
+	unicode.Han, unicode.Hiragana, unicode.Katakana,
+	unicode.Thai, unicode.Lao, unicode.Khmer, unicode.Myanmar,
+}
+
+// tokenize splits a line into words following the UAX #29 word-boundary rules
+// that matter for spell checking:
+//   - letters of any script form words (WB5);
+//   - combining marks, format characters and joiners stay attached to the
+//     preceding letter (WB4), so decomposed "café" is one word;
+//   - an apostrophe between letters does not break a word (WB6/WB7), so
+//     "they're" and "l’homme" are single tokens.
+//
+// As a tailoring for English prose, a hyphen between letters also joins, which
+// keeps "state-of-the-art" together. Digits and all other characters are word
+// boundaries, and text in scripts without spaces is skipped.
+func tokenize(line string) []token {
+	var tokens []token
+	start, startCol := -1, 0
+	col := 0
+	for i := 0; i < len(line); {
+		r, size := utf8.DecodeRuneInString(line[i:])
+		col++
+		switch {
+		case isWordLetter(r):
+			if start < 0 {
+				start, startCol = i, col
+			}
+		case start >= 0 && isWordExtend(r):
+			// Marks and joiners never start a word, but never end one either.
+		case start >= 0 && isMidLetter(r) && nextIsWordLetter(line[i+size:]):
+			// Joiner between two letters: the word continues.
+		default:
+			if start >= 0 {
+				tokens = append(tokens, token{Text: line[start:i], Column: startCol})
+				start = -1
+			}
+		}
+		i += size
+	}
+	if start >= 0 {
+		tokens = append(tokens, token{Text: line[start:], Column: startCol})
+	}
+	return tokens
+}
+
+// isWordLetter reports whether r can start or continue a word.
+func isWordLetter(r rune) bool {
+	return unicode.IsLetter(r) && !unicode.In(r, noSpaceScripts...)
+}
+
+// isWordExtend reports whether r attaches to the preceding letter (UAX #29 Extend, Format and ZWJ).
+func isWordExtend(r rune) bool {
+	return unicode.In(r, unicode.M, unicode.Cf)
+}
+
+// isMidLetter reports whether r joins two letters into a single word.
+func isMidLetter(r rune) bool {
+	switch r {
+	case '\'', '’', '-', '‐':
+		return true
+	}
+	return false
+}
+
+// nextIsWordLetter reports whether s begins with a word letter.
+func nextIsWordLetter(s string) bool {
+	r, _ := utf8.DecodeRuneInString(s)
+	return isWordLetter(r)
+}
+
+// splitIdentifier splits a camelCase or PascalCase token into its component words,
+// keeping acronyms together: "parseDictionary" becomes "parse", "Dictionary" and
+// "HTTPServer" becomes "HTTP", "Server". A plural acronym such as "URLs" is kept
+// whole. Each part carries its own column. Tokens without case changes are
+// returned unchanged; snake_case needs no splitting because '_' already separates words.
+func splitIdentifier(tok token) []token {
+	runes := []rune(tok.Text)
+	var parts []token
+	start := 0
+	for i := 1; i < len(runes); i++ {
+		if !unicode.IsUpper(runes[i]) {
+			continue
+		}
+		prev := lastLetterBefore(runes, i)
+		switch {
+		case unicode.IsLower(prev):
+			// "parseDictionary": lower to upper starts a new word.
+		case unicode.IsUpper(prev) && i+1 < len(runes) && unicode.IsLower(runes[i+1]) && !isPluralSuffix(runes, i+1):
+			// "HTTPServer": the last capital of a run starts the next word.
+		default:
+			continue
+		}
+		parts = append(parts, token{Text: string(runes[start:i]), Column: tok.Column + start})
+		start = i
+	}
+	if len(parts) == 0 {
+		return []token{tok}
+	}
+	return append(parts, token{Text: string(runes[start:]), Column: tok.Column + start})
+}
+
+// lastLetterBefore returns the closest letter before runes[i], skipping combining
+// marks and joiners, or 0 if there is none.
+func lastLetterBefore(runes []rune, i int) rune {
+	for j := i - 1; j >= 0; j-- {
+		if unicode.IsLetter(runes[j]) {
+			return runes[j]
+		}
+		if !isWordExtend(runes[j]) {
+			return 0
+		}
+	}
+	return 0
+}
+
+// isPluralSuffix reports whether runes[i] is a lone trailing "s", as in "URLs" or "IDs".
+func isPluralSuffix(runes []rune, i int) bool {
+	return runes[i] == 's' && (i+1 == len(runes) || !unicode.IsLetter(runes[i+1]))
+}
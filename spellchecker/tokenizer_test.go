@@ -0,0 +1,60 @@
+package spellchecker
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	testCases := []struct {
+		name string
+		line string
+		want []token
+	}{
+		{"plain words", "hello world", []token{{"hello", 1}, {"world", 7}}},
+		{"contraction", "they're here", []token{{"they're", 1}, {"here", 9}}},
+		{"typographic apostrophe", "l’homme", []token{{"l’homme", 1}}},
+		{"hyphenated word", "a state-of-the-art test", []token{{"a", 1}, {"state-of-the-art", 3}, {"test", 20}}},
+		{"quotes are not part of words", "'quoted' -dash-", []token{{"quoted", 2}, {"dash", 11}}},
+		{"precomposed diacritics", "naïve café", []token{{"naïve", 1}, {"café", 7}}},
+		{"combining marks stay attached", "cafe\u0301 ok", []token{{"cafe\u0301", 1}, {"ok", 7}}},
+		{"columns counted in runes", "Zürich über alles", []token{{"Zürich", 1}, {"über", 8}, {"alles", 13}}},
+		{"non-Latin script", "привет мир", []token{{"привет", 1}, {"мир", 8}}},
+		{"digits are boundaries", "abc123def", []token{{"abc", 1}, {"def", 7}}},
+		{"scripts without spaces are skipped", "日本語 text", []token{{"text", 5}}},
+		{"empty line", "", nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tokenize(tc.line); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("tokenize(%q) = %v; want %v", tc.line, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestSplitIdentifier(t *testing.T) {
+	testCases := []struct {
+		text string
+		want []token
+	}{
+		{"parseDictionary", []token{{"parse", 5}, {"Dictionary", 10}}},
+		{"GenerateTextReport", []token{{"Generate", 5}, {"Text", 13}, {"Report", 17}}},
+		{"HTTPServer", []token{{"HTTP", 5}, {"Server", 9}}},
+		{"newHTTPClient", []token{{"new", 5}, {"HTTP", 8}, {"Client", 12}}},
+		{"parseURLs", []token{{"parse", 5}, {"URLs", 10}}},
+		{"getX", []token{{"get", 5}, {"X", 8}}},
+		{"Hello", []token{{"Hello", 5}}},
+		{"HTML", []token{{"HTML", 5}}},
+		{"state-of-the-Art", []token{{"state-of-the-Art", 5}}},
+		{"größerWert", []token{{"größer", 5}, {"Wert", 11}}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.text, func(t *testing.T) {
+			if got := splitIdentifier(token{Text: tc.text, Column: 5}); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("splitIdentifier(%q) = %v; want %v", tc.text, got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,295 @@
+package spellchecker
+
+import (
+	"context"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// watchDelay is how long changes must settle before files are checked again, so that
+// an editor saving a file in several steps, or a branch switch touching many files,
+// triggers a single check.
+const watchDelay = 200 * time.Millisecond
+
+// watcher checks the files of a scan again as they change, for --watch. Only the
+// changed files are checked, with the same rules as the first scan: a new file is
+// checked only if the scan would have checked it.
+type watcher struct {
+	root       string
+	dictionary *Dictionary
+	opts       CheckOptions
+	// report is called with the results of every file after each check.
+	report func(map[string][]MisspelledWord, ScanSummary) error
+	delay  time.Duration
+
+	results map[string][]MisspelledWord
+	summary ScanSummary
+	fs      *fsnotify.Watcher
+	// file is set when root is a file; its directory is watched for it.
+	file bool
+	// filter and ignores decide which directories are watched.
+	filter  pathFilter
+	ignores *ignoreMatcher
+	// outputs are the regular files standard output and error are redirected to,
+	// which change with every report and are not checked again.
+	outputs []os.FileInfo
+}
+
+// runWatch watches root after a first check returned results and summary, and calls
+// report after every change until ctx is done.
+func runWatch(ctx context.Context, root string, dictionary *Dictionary, opts CheckOptions, results map[string][]MisspelledWord, summary ScanSummary, report func(map[string][]MisspelledWord, ScanSummary) error) error {
+	w, err := newWatcher(root, dictionary, opts, results, summary, report)
+	if err != nil {
+		return err
+	}
+	opts.logf("Watching %s for changes; press Ctrl-C to stop.\n", root)
+	return w.run(ctx)
+}
+
+func newWatcher(root string, dictionary *Dictionary, opts CheckOptions, results map[string][]MisspelledWord, summary ScanSummary, report func(map[string][]MisspelledWord, ScanSummary) error) (*watcher, error) {
+	info, err := os.Stat(root)
+	if err != nil {
+		return nil, err
+	}
+	fs, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	w := &watcher{
+		root:       filepath.Clean(root),
+		dictionary: dictionary,
+		opts:       opts,
+		report:     report,
+		delay:      watchDelay,
+		results:    results,
+		summary:    summary,
+		fs:         fs,
+		file:       !info.IsDir(),
+		filter:     newPathFilter(root, opts.Include, opts.Exclude),
+	}
+	for _, f := range []*os.File{os.Stdout, os.Stderr} {
+		if info, err := f.Stat(); err == nil && info.Mode().IsRegular() {
+			w.outputs = append(w.outputs, info)
+		}
+	}
+	if w.file {
+		// Editors often save by renaming a new file over the old one, which a watch
+		// on the file itself would not survive.
+		if err := fs.Add(filepath.Dir(w.root)); err != nil {
+			fs.Close()
+			return nil, err
+		}
+		return w, nil
+	}
+	if !opts.NoIgnore {
+		if w.ignores, err = newIgnoreMatcher(root); err != nil {
+			opts.logf("Error reading ignore files for %q: %v\n", root, err)
+			w.ignores = nil
+		}
+	}
+	w.watchTree(w.root, nil)
+	return w, nil
+}
+
+// watchTree watches dir and the directories below it that the scan does not skip,
+// and adds the files found to files, if not nil.
+func (w *watcher) watchTree(dir string, files map[string]bool) {
+	filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			// The directory may already be gone again; its removal will be seen.
+			return nil
+		}
+		if !info.IsDir() {
+			if files != nil {
+				files[path] = true
+			}
+			return nil
+		}
+		if path != w.root && w.skipDir(path) {
+			return filepath.SkipDir
+		}
+		if w.ignores != nil {
+			if err := w.ignores.loadDir(path); err != nil {
+				w.opts.logf("Error reading ignore files in %q: %v\n", path, err)
+			}
+		}
+		if err := w.fs.Add(path); err != nil {
+			w.opts.logf("Error watching %q: %v\n", path, err)
+		}
+		return nil
+	})
+}
+
+// skipDir reports whether the scan skips the directory path.
+func (w *watcher) skipDir(path string) bool {
+	return filepath.Base(path) == ".git" ||
+		w.filter.excluded(path, true) ||
+		(w.ignores != nil && w.ignores.ignored(path, true))
+}
+
+// run handles file system events until ctx is done. Changes are collected until
+// none has happened for the watch delay, then checked together.
+func (w *watcher) run(ctx context.Context) error {
+	defer w.fs.Close()
+	pending := make(map[string]bool)
+	timer := time.NewTimer(w.delay)
+	timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-w.fs.Events:
+			if !ok {
+				return nil
+			}
+			// Paths are cleaned to match those of the walk: events of the directory
+			// "." are named like "./notes.txt".
+			path := filepath.Clean(event.Name)
+			if event.Op == fsnotify.Chmod || (w.file && path != w.root) {
+				continue
+			}
+			pending[path] = true
+			timer.Reset(w.delay)
+		case err, ok := <-w.fs.Errors:
+			if !ok {
+				return nil
+			}
+			w.opts.logf("Error watching files: %v\n", err)
+		case <-timer.C:
+			if err := w.recheck(ctx, pending); err != nil {
+				if ctx.Err() != nil {
+					// Stopped in the middle of a check.
+					return nil
+				}
+				return err
+			}
+			pending = make(map[string]bool)
+		}
+	}
+}
+
+// recheck checks the changed paths again, forgets the removed ones, and reports all
+// the results if any of them changed.
+func (w *watcher) recheck(ctx context.Context, changed map[string]bool) error {
+	if w.file {
+		results, summary, err := runConcurrentChecker(ctx, w.root, w.dictionary, w.opts)
+		if err != nil {
+			return err
+		}
+		w.results, w.summary = results, summary
+		return w.done(len(summary.Files))
+	}
+
+	files := make(map[string]bool)
+	forgotten := false
+	for path := range changed {
+		if w.forget(path) {
+			forgotten = true
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		if !info.IsDir() {
+			if !w.output(info) {
+				files[path] = true
+			}
+		} else if !w.skipDir(path) {
+			// A new directory, or one moved here: watch it and check all it contains.
+			w.watchTree(path, files)
+		}
+	}
+
+	checked := 0
+	if len(files) > 0 {
+		ranges := make(map[string][]lineRange, len(files))
+		for path := range files {
+			if rel, err := filepath.Rel(w.root, path); err == nil {
+				ranges[filepath.ToSlash(rel)] = []lineRange{{1, math.MaxInt}}
+			}
+		}
+		changes, err := newChangeSet(w.root, ranges)
+		if err != nil {
+			return err
+		}
+		opts := w.opts
+		opts.Changes = changes
+		results, summary, err := runConcurrentChecker(ctx, w.root, w.dictionary, opts)
+		if err != nil {
+			return err
+		}
+		for path, typos := range results {
+			w.results[path] = typos
+		}
+		w.summary.Files = append(w.summary.Files, summary.Files...)
+		sort.Strings(w.summary.Files)
+		w.summary.FilesScanned = len(w.summary.Files)
+		w.summary.Errors = append(w.summary.Errors, summary.Errors...)
+		sortFileErrors(w.summary.Errors)
+		checked = len(summary.Files) + len(summary.Errors)
+	}
+	if checked == 0 && !forgotten {
+		// Only skipped files changed, such as an editor's swap files.
+		return nil
+	}
+	return w.done(checked)
+}
+
+// output reports whether the file is where standard output or error go.
+func (w *watcher) output(info os.FileInfo) bool {
+	for _, output := range w.outputs {
+		if os.SameFile(info, output) {
+			return true
+		}
+	}
+	return false
+}
+
+// forget removes the results of path, a file or a directory, and reports whether
+// there were any.
+func (w *watcher) forget(path string) bool {
+	under := func(file string) bool {
+		return file == path || strings.HasPrefix(file, path+string(filepath.Separator))
+	}
+	files := w.summary.Files[:0]
+	for _, file := range w.summary.Files {
+		if !under(file) {
+			files = append(files, file)
+		}
+	}
+	forgotten := len(files) < len(w.summary.Files)
+	w.summary.Files = files
+	w.summary.FilesScanned = len(files)
+	errs := w.summary.Errors[:0]
+	for _, e := range w.summary.Errors {
+		if !under(e.Path) {
+			errs = append(errs, e)
+		}
+	}
+	if len(errs) < len(w.summary.Errors) {
+		forgotten = true
+	}
+	w.summary.Errors = errs
+	for file := range w.results {
+		if under(file) {
+			delete(w.results, file)
+			forgotten = true
+		}
+	}
+	return forgotten
+}
+
+// done reports the results after checking n files.
+func (w *watcher) done(n int) error {
+	if err := w.report(w.results, w.summary); err != nil {
+		return err
+	}
+	w.opts.logf("%s: checked %d changed files; watching for changes.\n", time.Now().Format("15:04:05"), n)
+	return nil
+}
@@ -0,0 +1,134 @@
+package spellchecker
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestWatcher(t *testing.T) {
+	dir := t.TempDir()
+	createFile(t, dir, "a.txt", "hello wrld")
+	createFile(t, dir, "b.txt", "hello world")
+	createFile(t, dir, ".gitignore", "ignored/\n")
+
+	dictionary := &Dictionary{Words: map[string]struct{}{"hello": {}, "world": {}}}
+	opts := CheckOptions{Exclude: []string{"*.log"}}
+	results, summary, err := runConcurrentChecker(context.Background(), dir, dictionary, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type report struct {
+		typos map[string][]string
+		files int
+	}
+	reports := make(chan report, 10)
+	w, err := newWatcher(dir, dictionary, opts, results, summary, func(results map[string][]MisspelledWord, summary ScanSummary) error {
+		r := report{typos: make(map[string][]string), files: len(summary.Files)}
+		for path, typos := range results {
+			rel, _ := filepath.Rel(dir, path)
+			for _, typo := range typos {
+				r.typos[filepath.ToSlash(rel)] = append(r.typos[filepath.ToSlash(rel)], typo.Word)
+			}
+		}
+		reports <- r
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.delay = 20 * time.Millisecond
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- w.run(ctx) }()
+	defer func() {
+		cancel()
+		if err := <-done; err != nil {
+			t.Errorf("run() error = %v", err)
+		}
+	}()
+
+	next := func(step string, want map[string][]string, wantFiles int) {
+		t.Helper()
+		select {
+		case r := <-reports:
+			for _, words := range r.typos {
+				sort.Strings(words)
+			}
+			if !reflect.DeepEqual(r.typos, want) || r.files != wantFiles {
+				t.Fatalf("%s: report = %v with %d files, want %v with %d files", step, r.typos, r.files, want, wantFiles)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("%s: no report", step)
+		}
+	}
+
+	createFile(t, dir, "b.txt", "hello wrold")
+	next("changed file", map[string][]string{"a.txt": {"wrld"}, "b.txt": {"wrold"}}, 3)
+
+	// Skipped files do not trigger a report; the next change does, without them.
+	createFile(t, dir, "debug.log", "qwzx")
+	createFile(t, dir, "ignored/notes.txt", "qwzx")
+	createFile(t, dir, "docs/new/c.txt", "wolrd")
+	next("new directory", map[string][]string{"a.txt": {"wrld"}, "b.txt": {"wrold"}, "docs/new/c.txt": {"wolrd"}}, 4)
+
+	// Files are checked again in directories created since the watch started.
+	createFile(t, dir, "docs/new/c.txt", "world")
+	next("file in new directory", map[string][]string{"a.txt": {"wrld"}, "b.txt": {"wrold"}}, 4)
+
+	if err := os.Remove(filepath.Join(dir, "a.txt")); err != nil {
+		t.Fatal(err)
+	}
+	next("removed file", map[string][]string{"b.txt": {"wrold"}}, 3)
+
+	if err := os.RemoveAll(filepath.Join(dir, "docs")); err != nil {
+		t.Fatal(err)
+	}
+	next("removed directory", map[string][]string{"b.txt": {"wrold"}}, 2)
+}
+
+func TestWatcherFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "notes.txt")
+	os.WriteFile(path, []byte("hello wrld"), 0644)
+	dictionary := &Dictionary{Words: map[string]struct{}{"hello": {}, "world": {}}}
+	results, summary, _ := runConcurrentChecker(context.Background(), path, dictionary, CheckOptions{})
+
+	reports := make(chan int, 10)
+	w, err := newWatcher(path, dictionary, CheckOptions{}, results, summary, func(results map[string][]MisspelledWord, _ ScanSummary) error {
+		reports <- len(results[path])
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.delay = 20 * time.Millisecond
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- w.run(ctx) }()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	// Other files of the directory are not checked; saving by rename is followed.
+	os.WriteFile(filepath.Join(dir, "other.txt"), []byte("qwzx"), 0644)
+	tmp := filepath.Join(dir, ".notes.txt.tmp")
+	os.WriteFile(tmp, []byte("hello world"), 0644)
+	if err := os.Rename(tmp, path); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case n := <-reports:
+		if n != 0 {
+			t.Errorf("got %d typos after the fix, want 0", n)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no report")
+	}
+}